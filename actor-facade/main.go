@@ -6,6 +6,9 @@ import (
 
 	"github.com/pix303/cinecity/pkg/actor"
 	"github.com/pix303/localemgmt-go/api/pkg/router"
+	"github.com/pix303/localemgmt-go/domain/pkg/contexts"
+	"github.com/pix303/localemgmt-go/domain/pkg/glossary"
+	"github.com/pix303/localemgmt-go/domain/pkg/lang"
 	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/aggregate"
 	"github.com/pix303/localemgmt-go/domain/pkg/user"
 )
@@ -61,6 +64,51 @@ func main() {
 		slog.Error("error on startup user actor", slog.String("err", err.Error()))
 	}
 
+	glossaryActor, err := glossary.NewGlossaryActor()
+	if err != nil {
+		slog.Error("error on startup glossary actor", slog.String("err", err.Error()))
+		return
+	}
+
+	err = actor.RegisterActor(glossaryActor)
+	if err != nil {
+		slog.Error("error on startup glossary actor", slog.String("err", err.Error()))
+	}
+
+	langActor, err := lang.NewLangActor()
+	if err != nil {
+		slog.Error("error on startup lang actor", slog.String("err", err.Error()))
+		return
+	}
+
+	err = actor.RegisterActor(langActor)
+	if err != nil {
+		slog.Error("error on startup lang actor", slog.String("err", err.Error()))
+	}
+
+	// langs of items created before langs were canonicalized
+	err = aggregate.BackfillCanonicalLangs("system")
+	if err != nil {
+		slog.Error("error on backfill canonical langs", slog.String("err", err.Error()))
+	}
+
+	// langs of items created before the registry
+	err = lang.BackfillLangs("system")
+	if err != nil {
+		slog.Error("error on backfill langs", slog.String("err", err.Error()))
+	}
+
+	contextActor, err := contexts.NewContextActor()
+	if err != nil {
+		slog.Error("error on startup context actor", slog.String("err", err.Error()))
+		return
+	}
+
+	err = actor.RegisterActor(contextActor)
+	if err != nil {
+		slog.Error("error on startup context actor", slog.String("err", err.Error()))
+	}
+
 	startEvent := router.StartRouter{}
 	msg := actor.Message{
 		From: actor.NewAddress("local", "main"),
@@ -0,0 +1,28 @@
+package dto
+
+// ContextRequest creates a context; langs must be registered
+type ContextRequest struct {
+	Name                 string
+	Description          string
+	RequiredLangs        []string
+	DefaultReferenceLang string
+}
+
+type ContextsRequest struct {
+	IncludeArchived bool   `query:"archived"`
+	Parent          string `query:"parent"`
+}
+
+// ContextTreeRequest selects the subtree of Root, the whole tree if empty
+type ContextTreeRequest struct {
+	Root            string `query:"root"`
+	IncludeArchived bool   `query:"archived"`
+}
+
+type RenameContextRequest struct {
+	Name string
+}
+
+type RequiredLangsRequest struct {
+	Langs []string
+}
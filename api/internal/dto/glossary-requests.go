@@ -0,0 +1,15 @@
+package dto
+
+// GlossaryTermRequest is a glossary term in Lang with its approved translations by lang
+type GlossaryTermRequest struct {
+	Term          string
+	Lang          string
+	CaseSensitive bool
+	Forbidden     bool
+	Translations  map[string]string
+	Notes         string
+}
+
+type GlossaryRequest struct {
+	Lang string `query:"lang"`
+}
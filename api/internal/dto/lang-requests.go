@@ -0,0 +1,13 @@
+package dto
+
+// LangRequest registers or changes a lang; empty Direction and PluralCategories are the defaults of Code
+type LangRequest struct {
+	Code             string
+	DisplayName      string
+	Direction        string
+	PluralCategories []string
+}
+
+type LangsRequest struct {
+	EnabledOnly bool `query:"enabled"`
+}
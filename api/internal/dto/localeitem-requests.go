@@ -1,15 +1,84 @@
 package dto
 
 type CreateRequest struct {
-	Lang    string
-	Context string
-	Content string
+	Lang         string
+	Context      string
+	Content      string
+	Plurals      map[string]string
+	Key          string
+	MaxChars     int
+	MaxGraphemes int
+	Description  string
 }
 
 type UpdateRequest struct {
-	AggregateId string
-	Lang        string
-	Content     string
+	AggregateId     string
+	Lang            string
+	Content         string
+	Plurals         map[string]string
+	ExpectedVersion int
+}
+
+// BulkOperation is a create or update of a bulk request: Op is create or update and the other
+// fields are the ones of CreateRequest or UpdateRequest
+type BulkOperation struct {
+	Op              string
+	AggregateId     string
+	Lang            string
+	Context         string
+	Content         string
+	Plurals         map[string]string
+	Key             string
+	MaxChars        int
+	MaxGraphemes    int
+	Description     string
+	ExpectedVersion int
+}
+
+type BulkRequest struct {
+	Operations []BulkOperation
+}
+
+type ChangeContextRequest struct {
+	Context string
+}
+
+type ChangeReferenceLangRequest struct {
+	Lang string
+}
+
+// LengthLimitRequest sets the max length of the translations of an item; 0 is no limit
+type LengthLimitRequest struct {
+	MaxChars     int
+	MaxGraphemes int
+}
+
+type DescriptionRequest struct {
+	Description string
+}
+
+type TagRequest struct {
+	Tag string
+}
+
+type ChangeStatusRequest struct {
+	Status string
+}
+
+type RevertRequest struct {
+	Lang            string
+	Version         int
+	ExpectedVersion int
+}
+
+type AddCommentRequest struct {
+	Content  string
+	Lang     string
+	ParentId string
+}
+
+type EditCommentRequest struct {
+	Content string
 }
 
 type GetContextRequest struct {
@@ -21,7 +90,17 @@ type GetDetailRequest struct {
 }
 
 type SearchRequest struct {
-	Lang           string
-	Context        string
-	PartialContent string
+	Lang           string   `query:"lang"`
+	Context        string   `query:"context"`
+	PartialContent string   `query:"content"`
+	Page           int      `query:"page"`
+	PageSize       int      `query:"pageSize"`
+	Sort           string   `query:"sort"`
+	Tags           []string `query:"tag"`
+}
+
+type HistoryRequest struct {
+	Lang     string `query:"lang"`
+	Page     int    `query:"page"`
+	PageSize int    `query:"pageSize"`
 }
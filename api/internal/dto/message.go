@@ -1,5 +1,87 @@
 package dto
 
+import (
+	eventstore "github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/aggregate"
+)
+
 type Message struct {
 	Content string
 }
+
+// ContentValidationError is the body of content rejected by ICU MessageFormat checks
+type ContentValidationError struct {
+	Message           string
+	SyntaxErrors      []ContentSyntaxError
+	PlaceholderErrors []ContentPlaceholderError
+	GlossaryErrors    []ContentGlossaryViolation `json:",omitempty"`
+}
+
+// ContentSyntaxError is a syntax error in content or, if Form is set, in a plural form
+type ContentSyntaxError struct {
+	Form    string
+	Offset  int
+	Message string
+}
+
+// ContentPlaceholderError is a placeholder not matching the reference lang translation
+type ContentPlaceholderError struct {
+	Placeholder  string
+	Problem      string
+	ExpectedType string
+	ActualType   string
+}
+
+// ContentGlossaryViolation is a glossary term not respected by content: Found is the forbidden word
+// used, Expected the approved translation missing
+type ContentGlossaryViolation struct {
+	TermId    string
+	Term      string
+	Forbidden bool
+	Found     string `json:",omitempty"`
+	Expected  string `json:",omitempty"`
+}
+
+// UpdateResult is the stored update event with the glossary warnings of its content
+type UpdateResult struct {
+	eventstore.StoreEvent
+	GlossaryWarnings []ContentGlossaryViolation `json:",omitempty"`
+}
+
+// BulkResult is the body of a bulk request; with Success false no operation was stored
+type BulkResult struct {
+	Success bool
+	Results []BulkOperationResult
+}
+
+// BulkOperationResult is the outcome of the operation at Index; Error is set if it was rejected
+type BulkOperationResult struct {
+	Index            int
+	Op               string
+	AggregateId      string
+	Error            any                        `json:",omitempty"`
+	GlossaryWarnings []ContentGlossaryViolation `json:",omitempty"`
+}
+
+// ContextResult are the translations of a context with, if requested, its completeness
+type ContextResult struct {
+	aggregate.GetContextBodyResult
+	Completeness *ContextCompleteness `json:",omitempty"`
+}
+
+// ContextCompleteness reports how many items of a context are translated in its required langs:
+// Complete items have a translation in all of them
+type ContextCompleteness struct {
+	RequiredLangs []string
+	Total         int
+	Complete      int
+	Langs         []LangCompleteness
+}
+
+type LangCompleteness struct {
+	Lang       string
+	Translated int
+	Approved   int
+	Missing    int
+	Percentage float64
+}
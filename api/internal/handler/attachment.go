@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pix303/localemgmt-go/domain/pkg/blob"
+	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/aggregate"
+	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+var (
+	ErrVerifyAttachmentRequest   = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying attachment: file must be a png, jpeg, gif or webp image up to 10MB")
+	ErrVerifyAttachmentExistence = echo.NewHTTPError(http.StatusNotFound, "Error on verifying existence of attachment")
+	ErrStoreAttachment           = echo.NewHTTPError(http.StatusInternalServerError, "Error on store attachment")
+	ErrStoreAttachmentEvent      = echo.NewHTTPError(http.StatusInternalServerError, "Error on store attachment event")
+	ErrRetriveAttachment         = echo.NewHTTPError(http.StatusInternalServerError, "Error on retrive attachment")
+)
+
+const maxAttachmentSize = 10 << 20
+
+var attachmentContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// AddAttachment stores the file form field in blob store and add attachment added event
+func (handler *LocaleItemHandler) AddAttachment(c echo.Context) error {
+	aggregateId := c.Param("id")
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	// verify request
+	fileHeader, err := c.FormFile("file")
+	if err != nil || fileHeader.Size == 0 || fileHeader.Size > maxAttachmentSize {
+		return ErrVerifyAttachmentRequest
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ErrVerifyAttachmentRequest
+	}
+	defer file.Close()
+
+	// content type is sniffed from content: the declared one is not trusted
+	content := bufio.NewReader(file)
+	head, err := content.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return ErrVerifyAttachmentRequest
+	}
+	contentType := http.DetectContentType(head)
+	if !slices.Contains(attachmentContentTypes, contentType) {
+		return ErrVerifyAttachmentRequest
+	}
+
+	attachmentId := events.NewAttachmentID()
+	blobName := aggregate.AttachmentBlobName(aggregateId, attachmentId)
+	err = handler.blobs.Put(blobName, content)
+	if err != nil {
+		slog.Error("error on put attachment blob", slog.String("name", blobName), slog.String("err", err.Error()))
+		return ErrStoreAttachment
+	}
+
+	evt, err := events.NewAddAttachmentEvent(aggregateId, attachmentId, filepath.Base(fileHeader.Filename), contentType, fileHeader.Size, "todo")
+	if err != nil {
+		handler.removeBlob(blobName)
+		return err
+	}
+
+	err = appendItemEvent(evt, 0, ErrStoreAttachmentEvent)
+	if err != nil {
+		// the blob is removed only if the writer rejected the event: on other errors the event may
+		// be stored anyway
+		if errors.Is(err, ErrStaleVersion) || errors.Is(err, ErrKeyConflict) {
+			handler.removeBlob(blobName)
+		} else {
+			slog.Warn("attachment blob kept since its event may be stored", slog.String("name", blobName), slog.String("err", err.Error()))
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, evt)
+}
+
+// GetAttachment responds with the content of the attachment of a locale item
+func (handler *LocaleItemHandler) GetAttachment(c echo.Context) error {
+	aggregateId := c.Param("id")
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := item.GetAttachmentByID(c.Param("attachmentId"))
+	if err != nil {
+		return ErrVerifyAttachmentExistence
+	}
+
+	content, err := handler.blobs.Get(aggregate.AttachmentBlobName(item.AggregateID, attachment.Id))
+	if errors.Is(err, blob.ErrBlobNotFound) {
+		return ErrVerifyAttachmentExistence
+	}
+	if err != nil {
+		return ErrRetriveAttachment
+	}
+	defer content.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	return c.Stream(http.StatusOK, attachment.ContentType, content)
+}
+
+// RemoveAttachment add attachment removed event and deletes its content from blob store
+func (handler *LocaleItemHandler) RemoveAttachment(c echo.Context) error {
+	aggregateId := c.Param("id")
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	attachment, err := item.GetAttachmentByID(c.Param("attachmentId"))
+	if err != nil {
+		return ErrVerifyAttachmentExistence
+	}
+
+	evt, err := events.NewRemoveAttachmentEvent(aggregateId, attachment.Id, "todo")
+	if err != nil {
+		return err
+	}
+
+	err = addItemEvent(c, evt, 0, ErrStoreAttachmentEvent)
+	if err == nil {
+		handler.removeBlob(aggregate.AttachmentBlobName(item.AggregateID, attachment.Id))
+	}
+	return err
+}
+
+// removeAttachmentBlobs deletes the contents of all item attachments
+func (handler *LocaleItemHandler) removeAttachmentBlobs(item aggregate.LocaleItemAggregate) {
+	for _, attachment := range item.Attachments {
+		handler.removeBlob(aggregate.AttachmentBlobName(item.AggregateID, attachment.Id))
+	}
+}
+
+func (handler *LocaleItemHandler) removeBlob(name string) {
+	err := handler.blobs.Delete(name)
+	if err != nil && !errors.Is(err, blob.ErrBlobNotFound) {
+		slog.Warn("error on delete attachment blob", slog.String("name", name), slog.String("err", err.Error()))
+	}
+}
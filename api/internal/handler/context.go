@@ -0,0 +1,686 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pix303/cinecity/pkg/actor"
+	eventstore "github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/localemgmt-go/api/internal/dto"
+	"github.com/pix303/localemgmt-go/domain/pkg/contexts"
+	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/aggregate"
+	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+var (
+	ErrVerifyContextName          = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying context name: it must be set, at most 64 characters and without empty path segments")
+	ErrContextNotFound            = echo.NewHTTPError(http.StatusNotFound, "Error context not found")
+	ErrContextNameUsed            = echo.NewHTTPError(http.StatusConflict, "Error context name already used")
+	ErrContextArchived            = echo.NewHTTPError(http.StatusConflict, "Error context is archived")
+	ErrContextMoving              = echo.NewHTTPError(http.StatusConflict, "Error context items are moving after a rename: repeat it to resume the move")
+	ErrContextItemsNotMoved       = echo.NewHTTPError(http.StatusInternalServerError, "Error on moving context items: some are not moved, repeat the rename to resume the move")
+	ErrStoreContextAggregateEvent = echo.NewHTTPError(http.StatusInternalServerError, "Error on store context event")
+	ErrRetriveContexts            = echo.NewHTTPError(http.StatusInternalServerError, "Error on retrive contexts")
+)
+
+type ContextHandler struct {
+}
+
+func NewContextHandler() ContextHandler {
+	return ContextHandler{}
+}
+
+// GetContexts returns the contexts; archived query param includes the archived ones and parent one
+// selects the children of a context, the root ones if empty
+func (handler *ContextHandler) GetContexts(ctx echo.Context) error {
+	payload := dto.ContextsRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	body := contexts.RetriveContextsMessageBody{IncludeArchived: payload.IncludeArchived}
+	if ctx.QueryParams().Has("parent") {
+		body.Parent = &payload.Parent
+	}
+
+	msg := actor.NewMessage(
+		contexts.ContextActorAddress,
+		nil,
+		body,
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[contexts.RetriveContextsMessageBodyResult](msg)
+	if err != nil {
+		return ErrRetriveContexts
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// GetTree returns the context subtree of root query param, the whole tree if empty, with the
+// translation counts of every node; registered contexts without items are included
+func (handler *ContextHandler) GetTree(ctx echo.Context) error {
+	payload := dto.ContextTreeRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	if payload.Root != "" {
+		payload.Root, err = contexts.NormalizeName(payload.Root)
+		if err != nil {
+			return ErrVerifyContextName
+		}
+	}
+
+	countsMsg := actor.NewMessage(
+		aggregate.LocaleItemAggregateListAddress,
+		nil,
+		aggregate.GetContextTreeBody{Root: payload.Root, IncludeArchived: payload.IncludeArchived},
+		true,
+	)
+	countsResult, err := actor.SendMessageWithResponse[aggregate.GetContextTreeBodyResult](countsMsg)
+	if err != nil {
+		return ErrRetriveContexts
+	}
+
+	contextsMsg := actor.NewMessage(
+		contexts.ContextActorAddress,
+		nil,
+		contexts.RetriveContextsMessageBody{IncludeArchived: payload.IncludeArchived},
+		true,
+	)
+	contextsResult, err := actor.SendMessageWithResponse[contexts.RetriveContextsMessageBodyResult](contextsMsg)
+	if err != nil {
+		return ErrRetriveContexts
+	}
+	names := make([]string, 0, len(contextsResult.Contexts))
+	for _, c := range contextsResult.Contexts {
+		names = append(names, c.Name)
+	}
+
+	return ctx.JSON(http.StatusOK, contexts.NewTree(payload.Root, countsResult.Counts, names))
+}
+
+// GetContextSettings returns the context with id
+func (handler *ContextHandler) GetContextSettings(ctx echo.Context) error {
+	c, err := getContext(ctx.Param("id"), "")
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, c)
+}
+
+// CreateContext adds context created event; its name is reserved first, so it is not used by another
+// context also if created at the same time
+func (handler *ContextHandler) CreateContext(ctx echo.Context) error {
+	payload := dto.ContextRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	name, err := contexts.NormalizeName(payload.Name)
+	if err != nil {
+		return ErrVerifyContextName
+	}
+	requiredLangs, err := verifyLangs(payload.RequiredLangs)
+	if err != nil {
+		return err
+	}
+	if payload.DefaultReferenceLang != "" {
+		_, err = verifyLang(&payload.DefaultReferenceLang)
+		if err != nil {
+			return err
+		}
+	}
+
+	evt, err := contexts.NewCreateEvent(name, payload.Description, requiredLangs, payload.DefaultReferenceLang, "todo")
+	if err != nil {
+		return err
+	}
+
+	id := contexts.ID(evt.AggregateID)
+	err = reserveContextName(id, name)
+	if err != nil {
+		return err
+	}
+	err = storeEvent(evt)
+	if err != nil {
+		releaseContextName(id, name)
+		return ErrStoreContextAggregateEvent
+	}
+
+	return ctx.JSON(http.StatusOK, evt)
+}
+
+// RenameContext adds context renamed event and moves its subtree to the new name: descendant
+// contexts and locale items keep their path relative to the context. New names are reserved before
+// any event is stored and old ones released once all items are moved. If some items are not moved,
+// the context keeps the name they are moved from and repeating the rename resumes the move
+func (handler *ContextHandler) RenameContext(ctx echo.Context) error {
+	payload := dto.RenameContextRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	c, err := getEditableContext(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	name, err := contexts.NormalizeName(payload.Name)
+	if err != nil {
+		return ErrVerifyContextName
+	}
+	if c.MovingFrom != "" {
+		if name != c.Name {
+			return ErrContextMoving
+		}
+		return resumeContextRename(ctx, c)
+	}
+	// the items moved in its own subtree would be moved again on resume
+	if contexts.InSubtree(name, c.Name) {
+		return ErrVerifyContextName
+	}
+
+	// items already in the new subtree would mix with the renamed ones
+	items, err := getContextItems(name, true)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		return ErrContextNameUsed
+	}
+
+	err = verifyMoveContextItems(c.Name, name)
+	if err != nil {
+		return err
+	}
+
+	renames, err := newContextRenames(c.Name, name)
+	if err != nil {
+		return err
+	}
+	err = reserveContextNames(renames)
+	if err != nil {
+		return err
+	}
+	err = storeContextRenames(renames)
+	if err != nil {
+		return err
+	}
+
+	_, err = moveContextItems(c.Name, name, renames)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, renames[0].evt)
+}
+
+// resumeContextRename completes the rename of c: it renames the descendants still under the old name,
+// moves the items left there and responds with the items moved event of c
+func resumeContextRename(ctx echo.Context, c contexts.Context) error {
+	renames, err := newContextRenames(c.MovingFrom, c.Name)
+	if err != nil {
+		return err
+	}
+	err = reserveContextNames(renames)
+	if err != nil {
+		return err
+	}
+	err = storeContextRenames(renames)
+	if err != nil {
+		return err
+	}
+
+	renames = append([]contextRename{{id: c.Id, from: c.MovingFrom, to: c.Name}}, renames...)
+	evts, err := moveContextItems(c.MovingFrom, c.Name, renames)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, evts[0])
+}
+
+// DescribeContext adds context described event; an empty description removes it
+func (handler *ContextHandler) DescribeContext(ctx echo.Context) error {
+	payload := dto.DescriptionRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	c, err := getEditableContext(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	evt, err := contexts.NewDescribeEvent(c.Id, payload.Description, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreContextAggregateEvent)
+}
+
+// SetRequiredLangs adds required langs set event replacing them; langs must be registered
+func (handler *ContextHandler) SetRequiredLangs(ctx echo.Context) error {
+	payload := dto.RequiredLangsRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	c, err := getEditableContext(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	langs, err := verifyLangs(payload.Langs)
+	if err != nil {
+		return err
+	}
+
+	evt, err := contexts.NewSetRequiredLangsEvent(c.Id, langs, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreContextAggregateEvent)
+}
+
+// SetDefaultReferenceLang adds default reference lang set event; an empty lang removes it
+func (handler *ContextHandler) SetDefaultReferenceLang(ctx echo.Context) error {
+	payload := dto.ChangeReferenceLangRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	c, err := getEditableContext(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	if payload.Lang != "" {
+		_, err = verifyLang(&payload.Lang)
+		if err != nil {
+			return err
+		}
+	}
+
+	evt, err := contexts.NewSetDefaultReferenceLangEvent(c.Id, payload.Lang, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreContextAggregateEvent)
+}
+
+// ArchiveContext adds context archived event: new items can not be created or moved in it; a context
+// whose items are moving after a rename can not be archived before the move completes
+func (handler *ContextHandler) ArchiveContext(ctx echo.Context) error {
+	c, err := getEditableContext(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	if c.MovingFrom != "" {
+		return ErrContextMoving
+	}
+
+	evt, err := contexts.NewArchiveEvent(c.Id, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreContextAggregateEvent)
+}
+
+// contextParam returns the context name of id path param; path contexts are escaped as checkout%2Fpayment
+func contextParam(ctx echo.Context) (string, error) {
+	name, err := url.PathUnescape(ctx.Param("id"))
+	if err != nil {
+		return "", ErrVerifyContextName
+	}
+	return name, nil
+}
+
+// getContext returns the context with id or, if empty, with name
+func getContext(id string, name string) (contexts.Context, error) {
+	msg := actor.NewMessage(
+		contexts.ContextActorAddress,
+		nil,
+		contexts.RetriveContextMessageBody{Id: id, Name: name},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[contexts.RetriveContextMessageBodyResult](msg)
+	if errors.Is(err, contexts.ErrContextNotFound) {
+		return contexts.Context{}, ErrContextNotFound
+	}
+	if err != nil {
+		return contexts.Context{}, ErrRetriveContexts
+	}
+	return result.Context, nil
+}
+
+// getEditableContext returns the context with id if not archived
+func getEditableContext(id string) (contexts.Context, error) {
+	c, err := getContext(id, "")
+	if err != nil {
+		return c, err
+	}
+	if c.IsArchived {
+		return c, ErrContextArchived
+	}
+	return c, nil
+}
+
+// reserveContextName reserves name for the context with id; ErrContextNameUsed if reserved by another
+func reserveContextName(id string, name string) error {
+	msg := actor.NewMessage(
+		contexts.ContextActorAddress,
+		nil,
+		contexts.ReserveContextNameMessageBody{Id: id, Name: name},
+		true,
+	)
+	_, err := actor.SendMessageWithResponse[contexts.ContextNameMessageBodyResult](msg)
+	if errors.Is(err, contexts.ErrContextNameUsed) {
+		return ErrContextNameUsed
+	}
+	if err != nil {
+		return ErrStoreContextAggregateEvent
+	}
+	return nil
+}
+
+// releaseContextName releases name if reserved by the context with id; a failure is only logged,
+// the name stays reserved
+func releaseContextName(id string, name string) {
+	msg := actor.NewMessage(
+		contexts.ContextActorAddress,
+		nil,
+		contexts.ReleaseContextNameMessageBody{Id: id, Name: name},
+		true,
+	)
+	_, err := actor.SendMessageWithResponse[contexts.ContextNameMessageBodyResult](msg)
+	if err != nil {
+		slog.Error("context name not released", slog.String("id", id), slog.String("name", name), slog.String("err", err.Error()))
+	}
+}
+
+// contextRename is the renamed event of a context in a renamed subtree, from its name to the new one
+type contextRename struct {
+	id   string
+	from string
+	to   string
+	evt  eventstore.StoreEvent
+}
+
+// newContextRenames returns the renames of the contexts in the subtree of from to the same path under
+// name; the root one, if any, is the first. ErrContextMoving if the items of one of them are still
+// moving after another rename
+func newContextRenames(from string, name string) ([]contextRename, error) {
+	subtree, err := getSubtreeContexts(from)
+	if err != nil {
+		return nil, err
+	}
+
+	renames := make([]contextRename, 0, len(subtree))
+	for _, renamed := range subtree {
+		if renamed.MovingFrom != "" {
+			return nil, ErrContextMoving
+		}
+		to, err := contexts.NormalizeName(name + strings.TrimPrefix(renamed.Name, from))
+		if err != nil {
+			return nil, ErrVerifyContextName
+		}
+		evt, err := contexts.NewRenameEvent(renamed.Id, to, "todo")
+		if err != nil {
+			return nil, err
+		}
+		renames = append(renames, contextRename{id: renamed.Id, from: renamed.Name, to: to, evt: evt})
+	}
+	return renames, nil
+}
+
+// reserveContextNames reserves the new names of renames; if one can not be reserved, the ones reserved
+// before are released
+func reserveContextNames(renames []contextRename) error {
+	for i, rename := range renames {
+		err := reserveContextName(rename.id, rename.to)
+		if err != nil {
+			for _, reserved := range renames[:i] {
+				releaseContextName(reserved.id, reserved.to)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// storeContextRenames stores the renamed events of renames; if the first is not stored, their new names
+// are released, otherwise the renames are resumed by repeating them
+func storeContextRenames(renames []contextRename) error {
+	for i, rename := range renames {
+		err := storeEvent(rename.evt)
+		if err != nil && i == 0 {
+			for _, reserved := range renames {
+				releaseContextName(reserved.id, reserved.to)
+			}
+		}
+		if err != nil {
+			return ErrStoreContextAggregateEvent
+		}
+	}
+	return nil
+}
+
+// verifyMoveContextItems checks that the items in the subtree of from can be moved under to
+func verifyMoveContextItems(from string, to string) error {
+	msg := actor.NewMessage(
+		aggregate.LocaleItemWriterAddress,
+		nil,
+		aggregate.VerifyMoveContextItemsBody{From: from, To: to},
+		true,
+	)
+	_, err := actor.SendMessageWithResponse[aggregate.VerifyMoveContextItemsBodyResult](msg)
+	if errors.Is(err, contexts.ErrInvalidName) {
+		return ErrVerifyContextName
+	}
+	if err != nil {
+		return ErrRetriveContexts
+	}
+	return nil
+}
+
+// moveContextItems moves the items in the subtree of from to the same path under to and completes the
+// renames: it releases their old names and stores their items moved events, also of the contexts in
+// the new subtree renamed by a previous attempt. ErrContextItemsNotMoved if some items are not moved:
+// the renames are completed only when all are
+func moveContextItems(from string, to string, renames []contextRename) ([]eventstore.StoreEvent, error) {
+	msg := actor.NewMessage(
+		aggregate.LocaleItemWriterAddress,
+		nil,
+		aggregate.MoveContextItemsBody{From: from, To: to, UserID: "todo"},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[aggregate.MoveContextItemsBodyResult](msg)
+	if errors.Is(err, contexts.ErrInvalidName) {
+		return nil, ErrVerifyContextName
+	}
+	if err != nil {
+		return nil, ErrStoreContextEvent
+	}
+	if result.Failed > 0 {
+		return nil, ErrContextItemsNotMoved
+	}
+
+	subtree, err := getSubtreeContexts(to)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range subtree {
+		moving := c.MovingFrom != "" && contexts.InSubtree(c.MovingFrom, from)
+		if moving && !slices.ContainsFunc(renames, func(rename contextRename) bool { return rename.id == c.Id }) {
+			renames = append(renames, contextRename{id: c.Id, from: c.MovingFrom, to: c.Name})
+		}
+	}
+
+	evts := make([]eventstore.StoreEvent, 0, len(renames))
+	for _, rename := range renames {
+		releaseContextName(rename.id, rename.from)
+		evt, err := contexts.NewItemsMovedEvent(rename.id, "todo")
+		if err != nil {
+			return nil, err
+		}
+		err = storeEvent(evt)
+		if err != nil {
+			return nil, ErrStoreContextAggregateEvent
+		}
+		evts = append(evts, evt)
+	}
+	return evts, nil
+}
+
+// verifyItemContext normalizes the context name of an item and checks that its context, if defined,
+// is not archived and returns it
+func verifyItemContext(name *string) (contexts.Context, error) {
+	normalized, err := contexts.NormalizeName(*name)
+	if err != nil {
+		return contexts.Context{}, ErrVerifyContextName
+	}
+	*name = normalized
+
+	c, err := getContext("", normalized)
+	if errors.Is(err, ErrContextNotFound) {
+		return contexts.Context{}, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if c.IsArchived {
+		return c, ErrContextArchived
+	}
+	return c, nil
+}
+
+// verifyLangs returns the canonical codes of langs without duplicates; all must be registered
+func verifyLangs(langs []string) ([]string, error) {
+	result := make([]string, 0, len(langs))
+	for _, lang := range langs {
+		_, err := verifyLang(&lang)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(result, lang) {
+			result = append(result, lang)
+		}
+	}
+	return result, nil
+}
+
+// getContextItems returns the translations of all items in context, archived ones too; with subtree
+// the ones of its descendants too
+func getContextItems(context string, subtree bool) ([]aggregate.LocaleItemList, error) {
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateListAddress,
+		nil,
+		aggregate.GetContextBody{Id: context, Subtree: subtree, IncludeArchived: true},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[aggregate.GetContextBodyResult](msg)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// getSubtreeContexts returns the contexts, archived ones too, in the subtree of name by name
+func getSubtreeContexts(name string) ([]contexts.Context, error) {
+	msg := actor.NewMessage(
+		contexts.ContextActorAddress,
+		nil,
+		contexts.RetriveContextsMessageBody{IncludeArchived: true},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[contexts.RetriveContextsMessageBodyResult](msg)
+	if err != nil {
+		return nil, ErrRetriveContexts
+	}
+
+	subtree := make([]contexts.Context, 0)
+	for _, c := range result.Contexts {
+		if contexts.InSubtree(c.Name, name) {
+			subtree = append(subtree, c)
+		}
+	}
+	return subtree, nil
+}
+
+// newCompleteness reports how many items are translated in each required lang
+func newCompleteness(items []aggregate.LocaleItemList, requiredLangs []string) dto.ContextCompleteness {
+	if requiredLangs == nil {
+		requiredLangs = make([]string, 0)
+	}
+	result := dto.ContextCompleteness{
+		RequiredLangs: requiredLangs,
+		Langs:         make([]dto.LangCompleteness, 0, len(requiredLangs)),
+	}
+
+	translated := make(map[string]map[string]bool)
+	approved := make(map[string]map[string]bool)
+	for _, lang := range requiredLangs {
+		translated[lang] = make(map[string]bool)
+		approved[lang] = make(map[string]bool)
+	}
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if !seen[item.Id] {
+			seen[item.Id] = true
+			ids = append(ids, item.Id)
+		}
+		if _, ok := translated[item.Lang]; !ok || item.Content == "" {
+			continue
+		}
+		translated[item.Lang][item.Id] = true
+		if item.Status == events.ApprovedTranslationStatus {
+			approved[item.Lang][item.Id] = true
+		}
+	}
+	result.Total = len(ids)
+
+	for _, id := range ids {
+		complete := true
+		for _, lang := range requiredLangs {
+			complete = complete && translated[lang][id]
+		}
+		if complete {
+			result.Complete++
+		}
+	}
+
+	for _, lang := range requiredLangs {
+		lc := dto.LangCompleteness{
+			Lang:       lang,
+			Translated: len(translated[lang]),
+			Approved:   len(approved[lang]),
+			Missing:    result.Total - len(translated[lang]),
+		}
+		if result.Total > 0 {
+			lc.Percentage = float64(lc.Translated) * 100 / float64(result.Total)
+		}
+		result.Langs = append(result.Langs, lc)
+	}
+	return result
+}
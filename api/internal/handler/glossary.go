@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/localemgmt-go/api/internal/dto"
+	"github.com/pix303/localemgmt-go/domain/pkg/glossary"
+)
+
+var (
+	ErrVerifyTermRequest = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: term and lang")
+	ErrTermNotFound      = echo.NewHTTPError(http.StatusNotFound, "Error glossary term not found")
+	ErrStoreTerm         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store glossary term")
+	ErrRetriveTerms      = echo.NewHTTPError(http.StatusInternalServerError, "Error on retrive glossary terms")
+)
+
+type GlossaryHandler struct {
+}
+
+func NewGlossaryHandler() GlossaryHandler {
+	return GlossaryHandler{}
+}
+
+// GetTerms returns the glossary terms; lang query param is optional
+func (handler *GlossaryHandler) GetTerms(ctx echo.Context) error {
+	payload := dto.GlossaryRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	if payload.Lang != "" {
+		err = canonicalLang(&payload.Lang)
+		if err != nil {
+			return err
+		}
+	}
+
+	msg := actor.NewMessage(
+		glossary.GlossaryActorAddress,
+		nil,
+		glossary.RetriveTermsMessageBody{Lang: payload.Lang},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[glossary.RetriveTermsMessageBodyResult](msg)
+	if err != nil {
+		return ErrRetriveTerms
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// AddTerm adds a glossary term with a new id
+func (handler *GlossaryHandler) AddTerm(ctx echo.Context) error {
+	payload := dto.GlossaryTermRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	term, err := glossary.NewTerm(payload.Term, payload.Lang, payload.CaseSensitive, payload.Forbidden, payload.Translations, payload.Notes, "todo")
+	if errors.Is(err, glossary.ErrTermRequired) {
+		return ErrVerifyTermRequest
+	}
+	if err != nil {
+		return err
+	}
+
+	return saveTerm(ctx, term, true)
+}
+
+// UpdateTerm replaces the glossary term with id
+func (handler *GlossaryHandler) UpdateTerm(ctx echo.Context) error {
+	payload := dto.GlossaryTermRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if payload.Term == "" || payload.Lang == "" {
+		return ErrVerifyTermRequest
+	}
+	if payload.Translations == nil {
+		payload.Translations = make(map[string]string)
+	}
+
+	term := glossary.Term{
+		Id:            ctx.Param("id"),
+		Term:          payload.Term,
+		Lang:          payload.Lang,
+		CaseSensitive: payload.CaseSensitive,
+		Forbidden:     payload.Forbidden,
+		Translations:  payload.Translations,
+		Notes:         payload.Notes,
+		UpdatedAt:     time.Now().UTC(),
+		UpdatedBy:     "todo",
+	}
+
+	return saveTerm(ctx, term, false)
+}
+
+// DeleteTerm removes the glossary term with id
+func (handler *GlossaryHandler) DeleteTerm(ctx echo.Context) error {
+	msg := actor.NewMessage(
+		glossary.GlossaryActorAddress,
+		nil,
+		glossary.DeleteTermMessageBody{Id: ctx.Param("id")},
+		true,
+	)
+	_, err := actor.SendMessageWithResponse[glossary.DeleteTermMessageBodyResult](msg)
+	if errors.Is(err, glossary.ErrTermNotFound) {
+		return ErrTermNotFound
+	}
+	if err != nil {
+		return ErrStoreTerm
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// saveTerm stores term, with canonical langs, and responds with it
+func saveTerm(ctx echo.Context, term glossary.Term, isNew bool) error {
+	err := canonicalLang(&term.Lang)
+	if err != nil {
+		return err
+	}
+	translations := make(map[string]string, len(term.Translations))
+	for lang, translation := range term.Translations {
+		err = canonicalLang(&lang)
+		if err != nil {
+			return err
+		}
+		translations[lang] = translation
+	}
+	term.Translations = translations
+
+	msg := actor.NewMessage(
+		glossary.GlossaryActorAddress,
+		nil,
+		glossary.SaveTermMessageBody{Term: term, IsNew: isNew},
+		true,
+	)
+	_, err = actor.SendMessageWithResponse[glossary.SaveTermMessageBodyResult](msg)
+	if errors.Is(err, glossary.ErrTermNotFound) {
+		return ErrTermNotFound
+	}
+	if err != nil {
+		return ErrStoreTerm
+	}
+
+	return ctx.JSON(http.StatusOK, term)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/localemgmt-go/api/internal/dto"
+	registry "github.com/pix303/localemgmt-go/domain/pkg/lang"
+)
+
+var (
+	ErrVerifyLangRequest  = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying lang request: BCP 47 code, display name, direction ltr or rtl and CLDR plural categories")
+	ErrLangNotRegistered  = echo.NewHTTPError(http.StatusBadRequest, "Error lang is not registered or not enabled")
+	ErrLangNotFound       = echo.NewHTTPError(http.StatusNotFound, "Error lang not found")
+	ErrLangRegistered     = echo.NewHTTPError(http.StatusConflict, "Error lang already registered")
+	ErrLangAlreadyEnabled = echo.NewHTTPError(http.StatusConflict, "Error lang is already enabled")
+	ErrLangNotEnabled     = echo.NewHTTPError(http.StatusConflict, "Error lang is already disabled")
+	ErrStoreLangEvent     = echo.NewHTTPError(http.StatusInternalServerError, "Error on store lang event")
+	ErrRetriveLangs       = echo.NewHTTPError(http.StatusInternalServerError, "Error on retrive langs")
+)
+
+type LangHandler struct {
+}
+
+func NewLangHandler() LangHandler {
+	return LangHandler{}
+}
+
+// GetLangs returns the registered langs; enabled query param filters the enabled ones
+func (handler *LangHandler) GetLangs(ctx echo.Context) error {
+	payload := dto.LangsRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	msg := actor.NewMessage(
+		registry.LangActorAddress,
+		nil,
+		registry.RetriveLangsMessageBody{EnabledOnly: payload.EnabledOnly},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[registry.RetriveLangsMessageBodyResult](msg)
+	if err != nil {
+		return ErrRetriveLangs
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// GetLang returns the lang with code, in any BCP 47 form
+func (handler *LangHandler) GetLang(ctx echo.Context) error {
+	l, err := getLang(ctx.Param("code"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+// RegisterLang adds lang registered event; the lang is enabled
+func (handler *LangHandler) RegisterLang(ctx echo.Context) error {
+	payload := dto.LangRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	evt, err := registry.NewRegisterEvent(payload.Code, payload.DisplayName, payload.Direction, payload.PluralCategories, "todo")
+	if err != nil {
+		return ErrVerifyLangRequest
+	}
+
+	_, err = getLang(payload.Code)
+	if err == nil {
+		return ErrLangRegistered
+	}
+	if !errors.Is(err, ErrLangNotFound) {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreLangEvent)
+}
+
+// UpdateLang adds lang changed event replacing display name, direction and plural categories
+func (handler *LangHandler) UpdateLang(ctx echo.Context) error {
+	payload := dto.LangRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	l, err := getLang(ctx.Param("code"))
+	if err != nil {
+		return err
+	}
+
+	evt, err := registry.NewChangeEvent(l.Code, payload.DisplayName, payload.Direction, payload.PluralCategories, "todo")
+	if err != nil {
+		return ErrVerifyLangRequest
+	}
+
+	return addEvent(ctx, evt, ErrStoreLangEvent)
+}
+
+// EnableLang adds lang enabled event
+func (handler *LangHandler) EnableLang(ctx echo.Context) error {
+	l, err := getLang(ctx.Param("code"))
+	if err != nil {
+		return err
+	}
+	if l.Enabled {
+		return ErrLangAlreadyEnabled
+	}
+
+	evt, err := registry.NewEnableEvent(l.Code, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreLangEvent)
+}
+
+// DisableLang adds lang disabled event: new translations in lang are rejected, existing ones are kept
+func (handler *LangHandler) DisableLang(ctx echo.Context) error {
+	l, err := getLang(ctx.Param("code"))
+	if err != nil {
+		return err
+	}
+	if !l.Enabled {
+		return ErrLangNotEnabled
+	}
+
+	evt, err := registry.NewDisableEvent(l.Code, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addEvent(ctx, evt, ErrStoreLangEvent)
+}
+
+// getLang returns the registered lang with the canonical form of code
+func getLang(code string) (registry.Lang, error) {
+	canonical, err := registry.Canonicalize(code)
+	if err != nil {
+		return registry.Lang{}, ErrVerifyLangRequest
+	}
+
+	msg := actor.NewMessage(
+		registry.LangActorAddress,
+		nil,
+		registry.RetriveLangMessageBody{Code: canonical},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[registry.RetriveLangMessageBodyResult](msg)
+	if errors.Is(err, registry.ErrLangNotFound) {
+		return registry.Lang{}, ErrLangNotFound
+	}
+	if err != nil {
+		return registry.Lang{}, ErrRetriveLangs
+	}
+	return result.Lang, nil
+}
+
+// verifyLang replaces lang with its canonical form and returns it if registered and enabled
+func verifyLang(lang *string) (registry.Lang, error) {
+	l, err := getLang(*lang)
+	if errors.Is(err, ErrLangNotFound) || errors.Is(err, ErrVerifyLangRequest) || err == nil && !l.Enabled {
+		return registry.Lang{}, ErrLangNotRegistered
+	}
+	if err != nil {
+		return registry.Lang{}, err
+	}
+	*lang = l.Code
+	return l, nil
+}
+
+// canonicalLang replaces lang with its canonical form; the lang of an existing translation can be
+// disabled since, so registration is not verified
+func canonicalLang(lang *string) error {
+	canonical, err := registry.Canonicalize(*lang)
+	if err != nil {
+		return ErrVerifyLangRequest
+	}
+	*lang = canonical
+	return nil
+}
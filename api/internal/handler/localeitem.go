@@ -1,14 +1,30 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pix303/cinecity/pkg/actor"
+	eventstore "github.com/pix303/eventstore-go-v2/pkg/events"
 	"github.com/pix303/eventstore-go-v2/pkg/store"
 	"github.com/pix303/localemgmt-go/api/internal/dto"
+	"github.com/pix303/localemgmt-go/domain/pkg/blob"
+	"github.com/pix303/localemgmt-go/domain/pkg/glossary"
 	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/aggregate"
 	"github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+	"github.com/pix303/localemgmt-go/domain/pkg/messageformat"
+	"github.com/pix303/localemgmt-go/domain/pkg/plural"
+	"github.com/pix303/localemgmt-go/domain/pkg/textlen"
 )
 
 var (
@@ -18,9 +34,71 @@ var (
 	ErrEventStore               = echo.NewHTTPError(http.StatusInternalServerError, "Error eventstore")
 	ErrStoreCreateEvent         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store creating locale item event")
 	ErrStoreUpdateEvent         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store updating locale item event")
+	ErrVerifySearchRequest      = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying search parameters: page, pageSize and sort")
+	ErrAggregateArchived        = echo.NewHTTPError(http.StatusConflict, "Error locale item is archived")
+	ErrAggregateNotArchived     = echo.NewHTTPError(http.StatusConflict, "Error locale item is not archived")
+	ErrStoreDeleteEvent         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store deleting locale item event")
+	ErrStoreRestoreEvent        = echo.NewHTTPError(http.StatusInternalServerError, "Error on store restoring locale item event")
+	ErrVerifyLangExistence      = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying existence of translation lang")
+	ErrRemoveReferenceLang      = echo.NewHTTPError(http.StatusConflict, "Error reference lang can not be removed: choose another reference first")
+	ErrStoreRemoveEvent         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store removing translation event")
+	ErrVerifyContextRequest     = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: context must be set and different from current")
+	ErrStoreContextEvent        = echo.NewHTTPError(http.StatusInternalServerError, "Error on store changing context event")
+	ErrVerifyReferenceRequest   = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: lang must be set and different from current reference")
+	ErrStoreReferenceEvent      = echo.NewHTTPError(http.StatusInternalServerError, "Error on store changing reference lang event")
+	ErrVerifyStatusRequest      = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: status must be needs-review, approved or rejected")
+	ErrStatusTransition         = echo.NewHTTPError(http.StatusConflict, "Error translation status transition not allowed")
+	ErrStoreStatusEvent         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store changing translation status event")
+	ErrStaleVersion             = echo.NewHTTPError(http.StatusConflict, "Error locale item was modified: expected version is stale")
+	ErrVerifyKey                = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying key: only letters, digits, '.', '_' and '-' are allowed")
+	ErrKeyConflict              = echo.NewHTTPError(http.StatusConflict, "Error key already used in context")
+	ErrVerifyTimeTravelRequest  = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: only one of at (RFC3339) or version (>= 1)")
+	ErrVerifyPageRequest        = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying page parameters: page and pageSize")
+	ErrVerifyRevertRequest      = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: lang and version (>= 1) of an existing translation")
+	ErrRevertNoChange           = echo.NewHTTPError(http.StatusConflict, "Error translation has already the content of version")
+	ErrStoreRevertEvent         = echo.NewHTTPError(http.StatusInternalServerError, "Error on store reverting translation event")
+	ErrVerifyCommentRequest     = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: content, lang of an existing translation and parent comment")
+	ErrVerifyCommentExistence   = echo.NewHTTPError(http.StatusNotFound, "Error on verifying existence of comment")
+	ErrResolveComment           = echo.NewHTTPError(http.StatusConflict, "Error only open threads can be resolved")
+	ErrStoreCommentEvent        = echo.NewHTTPError(http.StatusInternalServerError, "Error on store comment event")
+	ErrVerifyBulkRequest        = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying bulk request: operations must be between 1 and 5000")
+	ErrVerifyBulkOperation      = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying bulk operation: op must be create or update")
+	ErrStoreBulkEvents          = echo.NewHTTPError(http.StatusInternalServerError, "Error on store bulk events")
+	ErrBulkAborted              = echo.NewHTTPError(http.StatusConflict, "Error operation not stored because another one of the bulk request was rejected")
+	ErrVerifyLengthLimitRequest = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying request parameters: max chars and max graphemes must be >= 0")
+	ErrStoreLengthLimitEvent    = echo.NewHTTPError(http.StatusInternalServerError, "Error on store changing length limit event")
+	ErrVerifyTag                = echo.NewHTTPError(http.StatusBadRequest, "Error on verifying tag: only lowercase letters, digits, '.', '_' and '-' are allowed")
+	ErrTagExists                = echo.NewHTTPError(http.StatusConflict, "Error locale item has already the tag")
+	ErrTagNotFound              = echo.NewHTTPError(http.StatusNotFound, "Error locale item has not the tag")
+	ErrStoreTagEvent            = echo.NewHTTPError(http.StatusInternalServerError, "Error on store tag event")
+	ErrStoreDescriptionEvent    = echo.NewHTTPError(http.StatusInternalServerError, "Error on store changing description event")
+	ErrGlossaryCheck            = echo.NewHTTPError(http.StatusInternalServerError, "Error on checking content against glossary")
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// statusEventBuilders maps a target translation status to the event reaching it
+var statusEventBuilders = map[string]func(aggregateID string, lang string, userID string) (eventstore.StoreEvent, error){
+	events.NeedsReviewTranslationStatus: events.NewRequestReviewEvent,
+	events.ApprovedTranslationStatus:    events.NewApproveEvent,
+	events.RejectedTranslationStatus:    events.NewRejectEvent,
+}
+
+const (
+	defaultPageSize   = 50
+	maxPageSize       = 200
+	maxBulkOperations = 5000
+)
+
+const (
+	bulkCreateOp = "create"
+	bulkUpdateOp = "update"
 )
 
 type LocaleItemHandler struct {
+	blobs blob.Store
 }
 
 func NewLocaleItemHandler() (LocaleItemHandler, error) {
@@ -35,11 +113,20 @@ func NewLocaleItemHandler() (LocaleItemHandler, error) {
 		return LocaleItemHandler{}, err
 	}
 
-	return LocaleItemHandler{}, nil
+	blobs, err := blob.NewStore()
+	if err != nil {
+		return LocaleItemHandler{}, err
+	}
+
+	return LocaleItemHandler{blobs: blobs}, nil
 }
 
 func (handler *LocaleItemHandler) GetDetail(ctx echo.Context) error {
 	aggregateId := ctx.Param("id")
+	if ctx.QueryParam("at") != "" || ctx.QueryParam("version") != "" {
+		return handler.getDetailAt(ctx, aggregateId)
+	}
+
 	msg := actor.NewMessage(
 		aggregate.LocaleItemAggregateDetailAddress,
 		nil,
@@ -60,13 +147,116 @@ func (handler *LocaleItemHandler) GetDetail(ctx echo.Context) error {
 	return nil
 }
 
+// getDetailAt returns the detail rebuilt by replaying events up to at time or version
+func (handler *LocaleItemHandler) getDetailAt(ctx echo.Context, aggregateId string) error {
+	body := aggregate.GetLocaleItemAggregateAtBody{Id: aggregateId}
+
+	// verify request
+	if ctx.QueryParam("at") != "" && ctx.QueryParam("version") != "" {
+		return ErrVerifyTimeTravelRequest
+	}
+	if at := ctx.QueryParam("at"); at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return ErrVerifyTimeTravelRequest
+		}
+		body.At = t
+	}
+	if version := ctx.QueryParam("version"); version != "" {
+		v, err := strconv.Atoi(version)
+		if err != nil || v < 1 {
+			return ErrVerifyTimeTravelRequest
+		}
+		body.Version = v
+	}
+
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateAddress,
+		nil,
+		body,
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[aggregate.GetLocaleItemAggregateAtBodyResult](msg)
+	if err != nil {
+		return ErrEventStore
+	}
+
+	if result.Aggregate.Version == 0 {
+		return echo.ErrNotFound
+	}
+
+	err = ctx.JSON(http.StatusOK, aggregate.GetLocaleItemAggregateDetailBodyResult{Aggregate: result.Aggregate})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetHistory returns the translation change history of a locale item read from event store
+func (handler *LocaleItemHandler) GetHistory(ctx echo.Context) error {
+	payload := dto.HistoryRequest{}
+	err := ctx.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if payload.Page == 0 {
+		payload.Page = 1
+	}
+	if payload.PageSize == 0 {
+		payload.PageSize = defaultPageSize
+	}
+	if payload.Page < 1 || payload.PageSize < 1 || payload.PageSize > maxPageSize {
+		return ErrVerifyPageRequest
+	}
+	if payload.Lang != "" {
+		err = canonicalLang(&payload.Lang)
+		if err != nil {
+			return err
+		}
+	}
+
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateAddress,
+		nil,
+		aggregate.GetLocaleItemHistoryBody{
+			Id:       ctx.Param("id"),
+			Lang:     payload.Lang,
+			Page:     payload.Page,
+			PageSize: payload.PageSize,
+		},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[aggregate.GetLocaleItemHistoryBodyResult](msg)
+	if err != nil {
+		return ErrEventStore
+	}
+
+	err = ctx.JSON(http.StatusOK, result)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetContext returns the translations of context, with subtree query param of its descendants too;
+// completeness query param adds how many items are translated in the required langs of context settings
 func (handler *LocaleItemHandler) GetContext(ctx echo.Context) error {
-	contextId := ctx.Param("id")
+	contextId, err := contextParam(ctx)
+	if err != nil {
+		return err
+	}
 	msg := actor.NewMessage(
 		aggregate.LocaleItemAggregateListAddress,
 		nil,
 		aggregate.GetContextBody{
-			Id: contextId,
+			Id:              contextId,
+			Subtree:         ctx.QueryParam("subtree") == "true",
+			IncludeArchived: ctx.QueryParam("archived") == "true",
+			Status:          ctx.QueryParam("status"),
+			StaleOnly:       ctx.QueryParam("stale") == "true",
+			Tags:            ctx.QueryParams()["tag"],
 		},
 		true,
 	)
@@ -76,6 +266,30 @@ func (handler *LocaleItemHandler) GetContext(ctx echo.Context) error {
 		return err
 	}
 
+	// completeness against the required langs of context settings, if any, of all the items not
+	// archived whatever the filters of the list
+	if ctx.QueryParam("completeness") == "true" {
+		c, err := getContext("", contextId)
+		if err != nil && !errors.Is(err, ErrContextNotFound) {
+			return err
+		}
+		allMsg := actor.NewMessage(
+			aggregate.LocaleItemAggregateListAddress,
+			nil,
+			aggregate.GetContextBody{
+				Id:      contextId,
+				Subtree: ctx.QueryParam("subtree") == "true",
+			},
+			true,
+		)
+		all, err := actor.SendMessageWithResponse[aggregate.GetContextBodyResult](allMsg)
+		if err != nil {
+			return err
+		}
+		completeness := newCompleteness(all.Items, c.RequiredLangs)
+		return ctx.JSON(http.StatusOK, dto.ContextResult{GetContextBodyResult: result, Completeness: &completeness})
+	}
+
 	err = ctx.JSON(http.StatusOK, result)
 	if err != nil {
 		return err
@@ -83,47 +297,119 @@ func (handler *LocaleItemHandler) GetContext(ctx echo.Context) error {
 	return nil
 }
 
-// CreateLocaleItem add crate locale item event
-func (handler *LocaleItemHandler) CreateLocaleItem(c echo.Context) error {
-	payload := dto.CreateRequest{}
-	err := c.Bind(&payload)
+// Search returns translations filtered by context, lang, partial content and tags
+func (handler *LocaleItemHandler) Search(ctx echo.Context) error {
+	payload := dto.SearchRequest{}
+	err := ctx.Bind(&payload)
 	if err != nil {
 		return err
 	}
 
 	// verify request
-	if payload.Content == "" || payload.Lang == "" {
-		return ErrVerifyRequest
+	if payload.Page == 0 {
+		payload.Page = 1
 	}
-
-	if payload.Context == "" {
-		payload.Context = aggregate.DEFAULT_CONTEXT
+	if payload.PageSize == 0 {
+		payload.PageSize = defaultPageSize
+	}
+	if payload.Sort == "" {
+		payload.Sort = "desc"
+	}
+	if payload.Page < 1 || payload.PageSize < 1 || payload.PageSize > maxPageSize {
+		return ErrVerifySearchRequest
+	}
+	if payload.Sort != "asc" && payload.Sort != "desc" {
+		return ErrVerifySearchRequest
+	}
+	if payload.Lang != "" {
+		err = canonicalLang(&payload.Lang)
+		if err != nil {
+			return err
+		}
 	}
 
-	// TODO: add check if for content + lang + context something exists
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateListAddress,
+		nil,
+		aggregate.SearchBody{
+			Context:        payload.Context,
+			Lang:           payload.Lang,
+			PartialContent: payload.PartialContent,
+			Page:           payload.Page,
+			PageSize:       payload.PageSize,
+			SortAsc:        payload.Sort == "asc",
+			Tags:           payload.Tags,
+		},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[aggregate.SearchBodyResult](msg)
+	if err != nil {
+		return err
+	}
 
-	evt, err := events.NewCreateEvent(payload.Content, payload.Context, payload.Lang, "todo")
+	err = ctx.JSON(http.StatusOK, result)
+	if err != nil {
+		return err
+	}
+	return nil
+}
 
+// GetByKey returns the translations of the locale item with key in context
+func (handler *LocaleItemHandler) GetByKey(ctx echo.Context) error {
+	contextId, err := contextParam(ctx)
 	if err != nil {
 		return err
 	}
 
 	msg := actor.NewMessage(
-		store.EventStoreAddress,
+		aggregate.LocaleItemAggregateListAddress,
 		nil,
-		store.AddEventBody{Event: evt},
+		aggregate.GetByKeyBody{
+			Context: contextId,
+			Key:     ctx.Param("key"),
+		},
 		true,
 	)
 
-	result, err := actor.SendMessageWithResponse[store.AddEventBodyResult](msg)
+	result, err := actor.SendMessageWithResponse[aggregate.GetByKeyBodyResult](msg)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Items) == 0 {
+		return echo.ErrNotFound
+	}
+
+	err = ctx.JSON(http.StatusOK, result)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateLocaleItem add crate locale item event
+func (handler *LocaleItemHandler) CreateLocaleItem(c echo.Context) error {
+	payload := dto.CreateRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	err = verifyCreateRequest(&payload)
 	if err != nil {
-		return ErrStoreCreateEvent
+		return err
 	}
 
-	if result.Success {
-		return c.JSON(http.StatusOK, evt)
+	// TODO: add check if for content + lang + context something exists
+
+	evt, err := events.NewCreateEvent(payload.Content, payload.Plurals, payload.Context, payload.Lang, payload.Key, payload.MaxChars, payload.MaxGraphemes, payload.Description, "todo")
+
+	if err != nil {
+		return err
 	}
-	return ErrStoreCreateEvent
+
+	return addItemEvent(c, evt, 0, ErrStoreCreateEvent)
 }
 
 // UpdateTranslation add add or update locale item translation event
@@ -134,45 +420,1091 @@ func (reqHandler *LocaleItemHandler) UpdateTranslation(c echo.Context) error {
 		return err
 	}
 
+	warnings, err := verifyUpdateRequest(&payload)
+	if err != nil {
+		return err
+	}
+
+	// add update event
+	evt, err := events.NewUpdateEvent(payload.AggregateId, payload.Content, payload.Plurals, payload.Lang, "todo")
+	if err != nil {
+		return err
+	}
+
+	// expected version is checked on append, with no other write in between
+	err = appendItemEvent(evt, payload.ExpectedVersion, ErrStoreUpdateEvent)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.UpdateResult{StoreEvent: evt, GlossaryWarnings: warnings})
+}
+
+// Bulk verifies all create and update operations, each update against a single snapshot of its
+// locale item, and appends their events all or none in one transaction; if one operation is rejected,
+// on verify or on append as the ones of items modified since their snapshot, nothing is stored and
+// the error of each operation is reported in its result
+func (handler *LocaleItemHandler) Bulk(c echo.Context) error {
+	payload := dto.BulkRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
 	// verify request
-	if payload.AggregateId == "" || payload.Content == "" || payload.Lang == "" {
-		return ErrVerifyRequest
+	if len(payload.Operations) == 0 || len(payload.Operations) > maxBulkOperations {
+		return ErrVerifyBulkRequest
 	}
 
-	// check aggregate id presence
-	checkMsg := actor.NewMessage(
-		store.EventStoreAddress,
-		nil,
-		store.CheckExistenceByAggregateIDBody{Id: payload.AggregateId},
-		true,
-	)
+	evts := make([]aggregate.AppendLocaleItemEvent, 0, len(payload.Operations))
+	results := make([]dto.BulkOperationResult, 0, len(payload.Operations))
+	keys := make(map[string]bool)
+	snapshots := make(map[string]*bulkSnapshot)
+	rejected := false
+	for i, op := range payload.Operations {
+		evt, warnings, err := newBulkEvent(op, keys, snapshots)
+		result := dto.BulkOperationResult{Index: i, Op: op.Op, GlossaryWarnings: warnings}
+		if err != nil {
+			rejected = true
+			result.Error = errorMessage(err)
+		} else {
+			result.AggregateId = evt.Event.AggregateID
+			evts = append(evts, evt)
+		}
+		results = append(results, result)
+	}
 
-	result, err := actor.SendMessageWithResponse[store.CheckExistenceByAggregateIDBodyResult](checkMsg)
-	if !result.Exists {
-		return ErrVerifyAggregateExistence
+	if rejected {
+		return c.JSON(http.StatusBadRequest, dto.BulkResult{Success: false, Results: results})
 	}
 
-	// add update event
-	evt, err := events.NewUpdateEvent(payload.AggregateId, payload.Content, payload.Lang, "todo")
+	errs, err := appendItemEventsBatch(evts)
+	if err != nil {
+		return ErrStoreBulkEvents
+	}
+
+	status := http.StatusOK
+	for i, err := range errs {
+		if err != nil {
+			appendErr := appendError(err, ErrStoreBulkEvents)
+			results[i].Error = appendErr.Message
+			status = max(status, appendErr.Code)
+		}
+	}
+
+	return c.JSON(status, dto.BulkResult{Success: status == http.StatusOK, Results: results})
+}
+
+// bulkSnapshot is the locale item of the update operations of a bulk request, read once, with the
+// number of its operations already verified
+type bulkSnapshot struct {
+	item       aggregate.LocaleItemAggregate
+	operations int
+}
+
+// newBulkEvent verifies a bulk operation and returns its event with glossary warnings; keys collects
+// the context keys created by previous operations of the same request and snapshots the locale items
+// updated by them
+func newBulkEvent(op dto.BulkOperation, keys map[string]bool, snapshots map[string]*bulkSnapshot) (aggregate.AppendLocaleItemEvent, []dto.ContentGlossaryViolation, error) {
+	switch op.Op {
+	case bulkCreateOp:
+		req := dto.CreateRequest{Lang: op.Lang, Context: op.Context, Content: op.Content, Plurals: op.Plurals, Key: op.Key, MaxChars: op.MaxChars, MaxGraphemes: op.MaxGraphemes, Description: op.Description}
+		err := verifyCreateRequest(&req)
+		if err != nil {
+			return aggregate.AppendLocaleItemEvent{}, nil, err
+		}
+		if req.Key != "" {
+			contextKey := req.Context + "/" + req.Key
+			if keys[contextKey] {
+				return aggregate.AppendLocaleItemEvent{}, nil, ErrKeyConflict
+			}
+			keys[contextKey] = true
+		}
+		evt, err := events.NewCreateEvent(req.Content, req.Plurals, req.Context, req.Lang, req.Key, req.MaxChars, req.MaxGraphemes, req.Description, "todo")
+		return aggregate.AppendLocaleItemEvent{Event: evt}, nil, err
+	case bulkUpdateOp:
+		req := dto.UpdateRequest{AggregateId: op.AggregateId, Lang: op.Lang, Content: op.Content, Plurals: op.Plurals, ExpectedVersion: op.ExpectedVersion}
+		snapshot, ok := snapshots[req.AggregateId]
+		if !ok {
+			item, err := getAggregate(req.AggregateId)
+			if err != nil {
+				return aggregate.AppendLocaleItemEvent{}, nil, err
+			}
+			snapshot = &bulkSnapshot{item: item}
+			snapshots[req.AggregateId] = snapshot
+		}
+		warnings, err := verifyUpdate(&req, snapshot.item)
+		if err != nil {
+			return aggregate.AppendLocaleItemEvent{}, nil, err
+		}
+		evt, err := events.NewUpdateEvent(req.AggregateId, req.Content, req.Plurals, req.Lang, "todo")
+		if err != nil {
+			return aggregate.AppendLocaleItemEvent{}, nil, err
+		}
+
+		// item must not change but for the previous operations of the request
+		expectedVersion := req.ExpectedVersion
+		if expectedVersion == 0 {
+			expectedVersion = snapshot.item.Version + snapshot.operations
+		}
+		snapshot.operations++
+		return aggregate.AppendLocaleItemEvent{Event: evt, ExpectedVersion: expectedVersion}, warnings, nil
+	}
+	return aggregate.AppendLocaleItemEvent{}, nil, ErrVerifyBulkOperation
+}
+
+// DeleteLocaleItem add archive locale item event or, with hard=true, delete locale item event
+func (handler *LocaleItemHandler) DeleteLocaleItem(c echo.Context) error {
+	aggregateId := c.Param("id")
+	item, err := getAggregateDetail(aggregateId)
 	if err != nil {
 		return err
 	}
 
-	addEventMsg := actor.NewMessage(
-		store.EventStoreAddress,
-		nil,
-		store.AddEventBody{Event: evt},
-		true,
-	)
+	var evt eventstore.StoreEvent
+	if c.QueryParam("hard") == "true" {
+		evt, err = events.NewDeleteEvent(aggregateId, "todo")
+	} else {
+		if item.IsArchived {
+			return ErrAggregateArchived
+		}
+		evt, err = events.NewArchiveEvent(aggregateId, "todo")
+	}
+	if err != nil {
+		return err
+	}
+
+	err = addItemEvent(c, evt, 0, ErrStoreDeleteEvent)
+	if err == nil && evt.EventType == events.DeleteLocaleItemStoreEventType {
+		// attachment contents of deleted items are not reachable anymore
+		handler.removeAttachmentBlobs(item)
+	}
+	return err
+}
+
+// RestoreLocaleItem add restore locale item event for an archived item
+func (handler *LocaleItemHandler) RestoreLocaleItem(c echo.Context) error {
+	aggregateId := c.Param("id")
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+
+	if !item.IsArchived {
+		return ErrAggregateNotArchived
+	}
+
+	evt, err := events.NewRestoreEvent(aggregateId, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreRestoreEvent)
+}
+
+// RemoveTranslation add remove translation event for a not reference lang
+func (handler *LocaleItemHandler) RemoveTranslation(c echo.Context) error {
+	aggregateId := c.Param("id")
+	lang := c.Param("lang")
+	err := canonicalLang(&lang)
+	if err != nil {
+		return err
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	_, err = item.GetTranslationItemByLang(lang)
+	if err != nil {
+		return ErrVerifyLangExistence
+	}
+	if item.ReferenceLang == lang {
+		return ErrRemoveReferenceLang
+	}
+
+	evt, err := events.NewRemoveTranslationEvent(aggregateId, lang, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreRemoveEvent)
+}
+
+// ChangeContext add context changed event to move locale item to another context
+func (handler *LocaleItemHandler) ChangeContext(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.ChangeContextRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
 
-	addResult, err := actor.SendMessageWithResponse[store.AddEventBodyResult](addEventMsg)
+	// verify request
+	if payload.Context == "" {
+		return ErrVerifyContextRequest
+	}
+	_, err = verifyItemContext(&payload.Context)
+	if err != nil {
+		return err
+	}
+	if payload.Context == item.Context {
+		return ErrVerifyContextRequest
+	}
+
+	evt, err := events.NewChangeContextEvent(aggregateId, payload.Context, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreContextEvent)
+}
+
+// ChangeReferenceLang add reference lang changed event; the new reference lang must have a translation
+func (handler *LocaleItemHandler) ChangeReferenceLang(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.ChangeReferenceLangRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	// verify request
+	if payload.Lang == "" {
+		return ErrVerifyReferenceRequest
+	}
+	err = canonicalLang(&payload.Lang)
+	if err != nil {
+		return err
+	}
+	if payload.Lang == item.ReferenceLang {
+		return ErrVerifyReferenceRequest
+	}
+	_, err = item.GetTranslationItemByLang(payload.Lang)
 	if err != nil {
-		return ErrStoreUpdateEvent
+		return ErrVerifyLangExistence
 	}
 
-	if addResult.Success {
-		return c.JSON(http.StatusOK, evt)
+	evt, err := events.NewChangeReferenceLangEvent(aggregateId, payload.Lang, "todo")
+	if err != nil {
+		return err
 	}
 
-	return ErrStoreUpdateEvent
+	return addItemEvent(c, evt, 0, ErrStoreReferenceEvent)
+}
+
+// ChangeStatus add the translation status event matching the requested workflow status
+func (handler *LocaleItemHandler) ChangeStatus(c echo.Context) error {
+	aggregateId := c.Param("id")
+	lang := c.Param("lang")
+	payload := dto.ChangeStatusRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	newEvent, ok := statusEventBuilders[payload.Status]
+	if !ok {
+		return ErrVerifyStatusRequest
+	}
+	err = canonicalLang(&lang)
+	if err != nil {
+		return err
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	translation, err := item.GetTranslationItemByLang(lang)
+	if err != nil {
+		return ErrVerifyLangExistence
+	}
+	if !translation.CanChangeStatus(payload.Status) {
+		return ErrStatusTransition
+	}
+
+	evt, err := newEvent(aggregateId, lang, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreStatusEvent)
+}
+
+// ChangeDescription add description changed event; an empty description removes it
+func (handler *LocaleItemHandler) ChangeDescription(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.DescriptionRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	evt, err := events.NewChangeDescriptionEvent(aggregateId, payload.Description, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreDescriptionEvent)
+}
+
+// ChangeLengthLimit add length limit changed event; 0 removes a limit
+func (handler *LocaleItemHandler) ChangeLengthLimit(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.LengthLimitRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if payload.MaxChars < 0 || payload.MaxGraphemes < 0 {
+		return ErrVerifyLengthLimitRequest
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+
+	evt, err := events.NewChangeLengthLimitEvent(aggregateId, payload.MaxChars, payload.MaxGraphemes, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreLengthLimitEvent)
+}
+
+// AddTag add tag added event
+func (handler *LocaleItemHandler) AddTag(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.TagRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if !tagPattern.MatchString(payload.Tag) {
+		return ErrVerifyTag
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+	if item.HasTag(payload.Tag) {
+		return ErrTagExists
+	}
+
+	evt, err := events.NewAddTagEvent(aggregateId, payload.Tag, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreTagEvent)
+}
+
+// RemoveTag add tag removed event
+func (handler *LocaleItemHandler) RemoveTag(c echo.Context) error {
+	aggregateId := c.Param("id")
+	tag := c.Param("tag")
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+	if !item.HasTag(tag) {
+		return ErrTagNotFound
+	}
+
+	evt, err := events.NewRemoveTagEvent(aggregateId, tag, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreTagEvent)
+}
+
+// RevertTranslation add translation reverted event with the content lang had at version
+func (handler *LocaleItemHandler) RevertTranslation(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.RevertRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if payload.Lang == "" || payload.Version < 1 {
+		return ErrVerifyRevertRequest
+	}
+	err = canonicalLang(&payload.Lang)
+	if err != nil {
+		return err
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+	if item.IsArchived {
+		return ErrAggregateArchived
+	}
+	if payload.Version > item.Version {
+		return ErrVerifyRevertRequest
+	}
+
+	// rebuild aggregate at version to read the historical content
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateAddress,
+		nil,
+		aggregate.GetLocaleItemAggregateAtBody{Id: aggregateId, Version: payload.Version},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[aggregate.GetLocaleItemAggregateAtBodyResult](msg)
+	if err != nil {
+		return ErrEventStore
+	}
+
+	target, err := result.Aggregate.GetTranslationItemByLang(payload.Lang)
+	if err != nil {
+		return ErrVerifyRevertRequest
+	}
+
+	current, err := item.GetTranslationItemByLang(payload.Lang)
+	if err == nil && current.Content == target.Content && maps.Equal(current.Plurals, target.Plurals) {
+		return ErrRevertNoChange
+	}
+
+	// length limit can be set after version
+	err = verifyLength(target.Content, target.Plurals, item.MaxChars, item.MaxGraphemes)
+	if err != nil {
+		return err
+	}
+
+	if payload.Lang != item.ReferenceLang {
+		args, err := verifyMessageFormat(target.Content, target.Plurals)
+		if err != nil {
+			return err
+		}
+		err = verifyPlaceholders(item, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	evt, err := events.NewRevertEvent(aggregateId, target.Content, target.Plurals, payload.Lang, payload.Version, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, payload.ExpectedVersion, ErrStoreRevertEvent)
+}
+
+// GetComments returns the comments of a locale item; lang and resolved=true query params are optional
+func (handler *LocaleItemHandler) GetComments(ctx echo.Context) error {
+	lang := ctx.QueryParam("lang")
+	if lang != "" {
+		err := canonicalLang(&lang)
+		if err != nil {
+			return err
+		}
+	}
+
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateCommentsAddress,
+		nil,
+		aggregate.GetCommentsBody{
+			AggregateId:     ctx.Param("id"),
+			Lang:            lang,
+			IncludeResolved: ctx.QueryParam("resolved") == "true",
+		},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[aggregate.GetCommentsBodyResult](msg)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.JSON(http.StatusOK, result)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddComment add comment added event as new thread or as reply to ParentId
+func (handler *LocaleItemHandler) AddComment(c echo.Context) error {
+	aggregateId := c.Param("id")
+	payload := dto.AddCommentRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if payload.Content == "" {
+		return ErrVerifyCommentRequest
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+
+	if payload.Lang != "" {
+		err = canonicalLang(&payload.Lang)
+		if err != nil {
+			return err
+		}
+		_, err = item.GetTranslationItemByLang(payload.Lang)
+		if err != nil {
+			return ErrVerifyCommentRequest
+		}
+	}
+	if payload.ParentId != "" {
+		parent, err := item.GetCommentByID(payload.ParentId)
+		if err != nil || parent.ParentId != "" {
+			return ErrVerifyCommentRequest
+		}
+	}
+
+	evt, err := events.NewAddCommentEvent(aggregateId, payload.ParentId, payload.Lang, payload.Content, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreCommentEvent)
+}
+
+// EditComment add comment edited event
+func (handler *LocaleItemHandler) EditComment(c echo.Context) error {
+	aggregateId := c.Param("id")
+	commentId := c.Param("commentId")
+	payload := dto.EditCommentRequest{}
+	err := c.Bind(&payload)
+	if err != nil {
+		return err
+	}
+
+	// verify request
+	if payload.Content == "" {
+		return ErrVerifyCommentRequest
+	}
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+
+	_, err = item.GetCommentByID(commentId)
+	if err != nil {
+		return ErrVerifyCommentExistence
+	}
+
+	evt, err := events.NewEditCommentEvent(aggregateId, commentId, payload.Content, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreCommentEvent)
+}
+
+// ResolveComment add comment resolved event for an open thread
+func (handler *LocaleItemHandler) ResolveComment(c echo.Context) error {
+	aggregateId := c.Param("id")
+	commentId := c.Param("commentId")
+
+	item, err := getAggregateDetail(aggregateId)
+	if err != nil {
+		return err
+	}
+
+	comment, err := item.GetCommentByID(commentId)
+	if err != nil {
+		return ErrVerifyCommentExistence
+	}
+	if comment.ParentId != "" || comment.IsResolved {
+		return ErrResolveComment
+	}
+
+	evt, err := events.NewResolveCommentEvent(aggregateId, commentId, "todo")
+	if err != nil {
+		return err
+	}
+
+	return addItemEvent(c, evt, 0, ErrStoreCommentEvent)
+}
+
+// verifyCreateRequest checks create request and sets its default context
+func verifyCreateRequest(payload *dto.CreateRequest) error {
+	if payload.Context == "" {
+		payload.Context = aggregate.DEFAULT_CONTEXT
+	}
+
+	// lang defaults to the reference lang of context settings
+	c, err := verifyItemContext(&payload.Context)
+	if err != nil {
+		return err
+	}
+	if payload.Lang == "" {
+		payload.Lang = c.DefaultReferenceLang
+	}
+	if payload.Lang == "" {
+		return ErrVerifyRequest
+	}
+	l, err := verifyLang(&payload.Lang)
+	if err != nil {
+		return err
+	}
+	err = verifyContent(l.PluralCategories, &payload.Content, payload.Plurals)
+	if err != nil {
+		return err
+	}
+	_, err = verifyMessageFormat(payload.Content, payload.Plurals)
+	if err != nil {
+		return err
+	}
+	if payload.MaxChars < 0 || payload.MaxGraphemes < 0 {
+		return ErrVerifyLengthLimitRequest
+	}
+	err = verifyLength(payload.Content, payload.Plurals, payload.MaxChars, payload.MaxGraphemes)
+	if err != nil {
+		return err
+	}
+
+	// key uniqueness in context is checked on append
+	if payload.Key != "" && !keyPattern.MatchString(payload.Key) {
+		return ErrVerifyKey
+	}
+	return nil
+}
+
+// verifyUpdateRequest checks update request against the current state of its locale item and the
+// glossary; terminology violations not blocking the update are returned as warnings
+func verifyUpdateRequest(payload *dto.UpdateRequest) ([]dto.ContentGlossaryViolation, error) {
+	if payload.AggregateId == "" {
+		return nil, ErrVerifyRequest
+	}
+
+	// check aggregate id presence
+	checkMsg := actor.NewMessage(
+		store.EventStoreAddress,
+		nil,
+		store.CheckExistenceByAggregateIDBody{Id: payload.AggregateId},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[store.CheckExistenceByAggregateIDBodyResult](checkMsg)
+	if err != nil || !result.Exists {
+		return nil, ErrVerifyAggregateExistence
+	}
+
+	item, err := getAggregateDetail(payload.AggregateId)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyUpdate(payload, item)
+}
+
+// verifyUpdate checks update request against item and the glossary
+func verifyUpdate(payload *dto.UpdateRequest, item aggregate.LocaleItemAggregate) ([]dto.ContentGlossaryViolation, error) {
+	if payload.Lang == "" {
+		return nil, ErrVerifyRequest
+	}
+	l, err := verifyLang(&payload.Lang)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyContent(l.PluralCategories, &payload.Content, payload.Plurals)
+	if err != nil {
+		return nil, err
+	}
+
+	// archived or deleted items are read only
+	if item.IsDeleted {
+		return nil, ErrVerifyAggregateExistence
+	}
+	if item.IsArchived {
+		return nil, ErrAggregateArchived
+	}
+	err = verifyLength(payload.Content, payload.Plurals, item.MaxChars, item.MaxGraphemes)
+	if err != nil {
+		return nil, err
+	}
+
+	// content must be valid and use the same placeholders of reference translation
+	args, err := verifyMessageFormat(payload.Content, payload.Plurals)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Lang != item.ReferenceLang {
+		err = verifyPlaceholders(item, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return verifyGlossary(item, payload.Lang, payload.Content)
+}
+
+// verifyGlossary checks content in lang against the glossary terms of item reference translation:
+// forbidden terms are errors, missing approved translations are warnings
+func verifyGlossary(item aggregate.LocaleItemAggregate, lang string, content string) ([]dto.ContentGlossaryViolation, error) {
+	referenceContent := content
+	if lang != item.ReferenceLang {
+		reference, err := item.GetTranslationItemByLang(item.ReferenceLang)
+		if err == nil {
+			referenceContent = reference.Content
+		}
+	}
+
+	msg := actor.NewMessage(
+		glossary.GlossaryActorAddress,
+		nil,
+		glossary.CheckMessageBody{
+			ReferenceLang:    item.ReferenceLang,
+			ReferenceContent: referenceContent,
+			Lang:             lang,
+			Content:          content,
+		},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[glossary.CheckMessageBodyResult](msg)
+	if err != nil {
+		return nil, ErrGlossaryCheck
+	}
+
+	warnings := make([]dto.ContentGlossaryViolation, 0)
+	forbidden := make([]dto.ContentGlossaryViolation, 0)
+	for _, v := range result.Violations {
+		if v.Forbidden {
+			forbidden = append(forbidden, dto.ContentGlossaryViolation(v))
+		} else {
+			warnings = append(warnings, dto.ContentGlossaryViolation(v))
+		}
+	}
+
+	if len(forbidden) > 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, dto.ContentValidationError{
+			Message:        "Error on verifying content: forbidden glossary terms",
+			GlossaryErrors: forbidden,
+		})
+	}
+	return warnings, nil
+}
+
+// getAggregateDetail retrives the aggregate from detail projection; deleted items are not found
+func getAggregateDetail(aggregateId string) (aggregate.LocaleItemAggregate, error) {
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateDetailAddress,
+		nil,
+		aggregate.GetLocaleItemAggregateDetailBody{
+			Id: aggregateId,
+		},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[aggregate.GetLocaleItemAggregateDetailBodyResult](msg)
+	if err != nil {
+		return aggregate.LocaleItemAggregate{}, err
+	}
+
+	if result.Aggregate.AggregateID == "" {
+		return aggregate.LocaleItemAggregate{}, ErrVerifyAggregateExistence
+	}
+
+	return result.Aggregate, nil
+}
+
+// verifyContent checks that content or the forms of the lang plural categories are given;
+// with plural forms, content defaults to the other form
+func verifyContent(pluralCategories []string, content *string, plurals map[string]string) error {
+	if len(plurals) == 0 {
+		if *content == "" {
+			return ErrVerifyRequest
+		}
+		return nil
+	}
+
+	err := plural.ValidateCategories(pluralCategories, plurals)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Error on verifying plural forms: %s", strings.ReplaceAll(err.Error(), "\n", "; ")))
+	}
+
+	if *content == "" {
+		*content = plurals[plural.Other]
+	}
+	return nil
+}
+
+// verifyLength checks that content and plural forms are not longer than maxChars characters and
+// maxGraphemes graphemes; 0 is no limit
+func verifyLength(content string, plurals map[string]string, maxChars int, maxGraphemes int) error {
+	forms := map[string]string{"": content}
+	for category, form := range plurals {
+		forms[category] = form
+	}
+
+	categories := slices.Sorted(maps.Keys(forms))
+	for _, category := range categories {
+		form := forms[category]
+		if !textlen.Exceeds(form, maxChars, maxGraphemes) {
+			continue
+		}
+		message := fmt.Sprintf("Error on verifying content length: %d characters (max %d), %d graphemes (max %d)", textlen.Chars(form), maxChars, textlen.Graphemes(form), maxGraphemes)
+		if category != "" {
+			message += " in plural form " + category
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, message)
+	}
+	return nil
+}
+
+// verifyMessageFormat parses content and plural forms as ICU MessageFormat and returns all their arguments
+func verifyMessageFormat(content string, plurals map[string]string) (map[string]string, error) {
+	args := make(map[string]string)
+	syntaxErrors := make([]dto.ContentSyntaxError, 0)
+
+	forms := map[string]string{"": content}
+	for category, form := range plurals {
+		forms[category] = form
+	}
+
+	for form, formContent := range forms {
+		formArgs, err := messageformat.Parse(formContent)
+		if err != nil {
+			syntaxErr, ok := err.(messageformat.SyntaxError)
+			if !ok {
+				return nil, err
+			}
+			syntaxErrors = append(syntaxErrors, dto.ContentSyntaxError{
+				Form:    form,
+				Offset:  syntaxErr.Offset,
+				Message: syntaxErr.Message,
+			})
+			continue
+		}
+		for name, argType := range formArgs {
+			if current, ok := args[name]; !ok || current == messageformat.NoneArgType {
+				args[name] = argType
+			}
+		}
+	}
+
+	if len(syntaxErrors) > 0 {
+		sort.Slice(syntaxErrors, func(i, j int) bool {
+			return syntaxErrors[i].Form < syntaxErrors[j].Form
+		})
+		return nil, echo.NewHTTPError(http.StatusBadRequest, dto.ContentValidationError{
+			Message:      "Error on verifying content: invalid ICU MessageFormat",
+			SyntaxErrors: syntaxErrors,
+		})
+	}
+	return args, nil
+}
+
+// verifyPlaceholders checks that args match the placeholders of item reference translation
+func verifyPlaceholders(item aggregate.LocaleItemAggregate, args map[string]string) error {
+	reference, err := item.GetTranslationItemByLang(item.ReferenceLang)
+	if err != nil {
+		return nil
+	}
+
+	// a reference stored before validation can be invalid: it can not be compared
+	referenceArgs, err := verifyMessageFormat(reference.Content, reference.Plurals)
+	if err != nil {
+		slog.Warn("reference translation is not valid ICU MessageFormat", slog.String("aggregateId", item.AggregateID))
+		return nil
+	}
+
+	placeholderErrors := messageformat.CompareArguments(referenceArgs, args)
+	if len(placeholderErrors) == 0 {
+		return nil
+	}
+
+	result := dto.ContentValidationError{
+		Message:           "Error on verifying content: placeholders differ from reference lang " + item.ReferenceLang,
+		PlaceholderErrors: make([]dto.ContentPlaceholderError, 0, len(placeholderErrors)),
+	}
+	for _, pe := range placeholderErrors {
+		result.PlaceholderErrors = append(result.PlaceholderErrors, dto.ContentPlaceholderError(pe))
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, result)
+}
+
+// storeEvent stores the event through the event store, that notifies it to subscribers
+func storeEvent(evt eventstore.StoreEvent) error {
+	msg := actor.NewMessage(
+		store.EventStoreAddress,
+		nil,
+		store.AddEventBody{Event: evt},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[store.AddEventBodyResult](msg)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return ErrEventStore
+	}
+	return nil
+}
+
+// addEvent stores the event and responds with it
+func addEvent(c echo.Context, evt eventstore.StoreEvent, errOnStore *echo.HTTPError) error {
+	err := storeEvent(evt)
+	if err != nil {
+		return errOnStore
+	}
+	return c.JSON(http.StatusOK, evt)
+}
+
+// addItemEvent appends the locale item event through the locale item writer and responds with it;
+// with expectedVersion the event is appended only if its item is still at that version
+func addItemEvent(c echo.Context, evt eventstore.StoreEvent, expectedVersion int, errOnStore *echo.HTTPError) error {
+	err := appendItemEvent(evt, expectedVersion, errOnStore)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, evt)
+}
+
+// appendItemEvent appends the locale item event through the locale item writer; ErrStaleVersion if
+// its item moved from expectedVersion, when given
+func appendItemEvent(evt eventstore.StoreEvent, expectedVersion int, errOnStore *echo.HTTPError) error {
+	errs, err := appendItemEvents([]aggregate.AppendLocaleItemEvent{{Event: evt, ExpectedVersion: expectedVersion}})
+	if err != nil || len(errs) != 1 {
+		return errOnStore
+	}
+	if errs[0] != nil {
+		return appendError(errs[0], errOnStore)
+	}
+	return nil
+}
+
+// appendItemEvents appends locale item events through the locale item writer and returns, at the
+// index of each event, nil if appended or the error rejecting it
+func appendItemEvents(evts []aggregate.AppendLocaleItemEvent) ([]error, error) {
+	if len(evts) == 0 {
+		return nil, nil
+	}
+
+	msg := actor.NewMessage(
+		aggregate.LocaleItemWriterAddress,
+		nil,
+		aggregate.AppendLocaleItemEventsBody{Events: evts},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[aggregate.AppendLocaleItemEventsBodyResult](msg)
+	if err != nil {
+		return nil, err
+	}
+	return result.Errors, nil
+}
+
+// appendItemEventsBatch appends locale item events all or none through the locale item writer and
+// returns, at the index of each event, nil if appended or the error rejecting it
+func appendItemEventsBatch(evts []aggregate.AppendLocaleItemEvent) ([]error, error) {
+	msg := actor.NewMessage(
+		aggregate.LocaleItemWriterAddress,
+		nil,
+		aggregate.AppendLocaleItemEventsBatchBody{Events: evts},
+		true,
+	)
+
+	result, err := actor.SendMessageWithResponse[aggregate.AppendLocaleItemEventsBodyResult](msg)
+	if err != nil {
+		return nil, err
+	}
+	return result.Errors, nil
+}
+
+// appendError returns the response error of an event rejected by the locale item writer
+func appendError(err error, errOnStore *echo.HTTPError) *echo.HTTPError {
+	if errors.Is(err, aggregate.ErrStaleVersion) {
+		return ErrStaleVersion
+	}
+	if errors.Is(err, aggregate.ErrKeyConflict) {
+		return ErrKeyConflict
+	}
+	if errors.Is(err, aggregate.ErrBatchAborted) {
+		return ErrBulkAborted
+	}
+	return errOnStore
+}
+
+// errorMessage returns the message of a response error, the text of any other error
+func errorMessage(err error) any {
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		return httpErr.Message
+	}
+	return err.Error()
+}
+
+// getAggregate returns the current state of the locale item, rebuilt from its events: the detail
+// projection can lag behind them
+func getAggregate(aggregateId string) (aggregate.LocaleItemAggregate, error) {
+	msg := actor.NewMessage(
+		aggregate.LocaleItemAggregateAddress,
+		nil,
+		aggregate.GetLocaleItemAggregateAtBody{Id: aggregateId},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[aggregate.GetLocaleItemAggregateAtBodyResult](msg)
+	if err != nil {
+		return aggregate.LocaleItemAggregate{}, ErrEventStore
+	}
+
+	if result.Aggregate.AggregateID == "" {
+		return aggregate.LocaleItemAggregate{}, ErrVerifyAggregateExistence
+	}
+	return result.Aggregate, nil
 }
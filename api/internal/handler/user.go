@@ -46,6 +46,7 @@ var (
 	ErrCreateJWTUserSession = echo.NewHTTPError(http.StatusInternalServerError, "fail to create user session cookie value")
 	ErrRetriveUserInfo      = echo.NewHTTPError(http.StatusInternalServerError, "fail to retrive user info")
 	ErrRevokeGoogleTokens   = echo.NewHTTPError(http.StatusInternalServerError, "fail to revoke tokens")
+	ErrAdminRoleRequired    = echo.NewHTTPError(http.StatusForbidden, "admin role required")
 )
 
 type UserHandler struct {
@@ -331,3 +332,20 @@ func (handler *UserHandler) SessionValidator() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// AdminValidator allows only users with admin role; it must follow SessionValidator
+func (handler *UserHandler) AdminValidator() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			u, err := getUserInfo(ctx)
+			if err != nil {
+				slog.Error("fail to get user info", slog.Any("error", err))
+				return ErrRetriveUserInfo
+			}
+			if u.Role != user.Admin {
+				return ErrAdminRoleRequired
+			}
+			return next(ctx)
+		}
+	}
+}
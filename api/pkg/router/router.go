@@ -32,6 +32,9 @@ func NewRouter() (*LocaleItemRouter, error) {
 	}))
 
 	userHandler := handler.NewUserHandler()
+	glossaryHandler := handler.NewGlossaryHandler()
+	langHandler := handler.NewLangHandler()
+	contextHandler := handler.NewContextHandler()
 	localeHandler, err := handler.NewLocaleItemHandler()
 	if err != nil {
 		return nil, err
@@ -61,8 +64,58 @@ func NewRouter() (*LocaleItemRouter, error) {
 	localeItemGroup.Use(userHandler.SessionValidator())
 	localeItemGroup.POST("/create", localeHandler.CreateLocaleItem)
 	localeItemGroup.POST("/update", localeHandler.UpdateTranslation)
+	localeItemGroup.POST("/bulk", localeHandler.Bulk)
 	localeItemGroup.GET("/detail/:id", localeHandler.GetDetail)
+	localeItemGroup.GET("/detail/:id/attachments/:attachmentId", localeHandler.GetAttachment)
 	localeItemGroup.GET("/context/:id", localeHandler.GetContext)
+	localeItemGroup.GET("/context/:id/key/:key", localeHandler.GetByKey)
+	localeItemGroup.GET("/search", localeHandler.Search)
+	localeItemGroup.DELETE("/:id", localeHandler.DeleteLocaleItem)
+	localeItemGroup.POST("/:id/restore", localeHandler.RestoreLocaleItem)
+	localeItemGroup.GET("/:id/history", localeHandler.GetHistory)
+	localeItemGroup.POST("/:id/revert", localeHandler.RevertTranslation)
+	localeItemGroup.GET("/:id/comments", localeHandler.GetComments)
+	localeItemGroup.POST("/:id/comments", localeHandler.AddComment)
+	localeItemGroup.PUT("/:id/comments/:commentId", localeHandler.EditComment)
+	localeItemGroup.POST("/:id/comments/:commentId/resolve", localeHandler.ResolveComment)
+	localeItemGroup.DELETE("/:id/translation/:lang", localeHandler.RemoveTranslation)
+	localeItemGroup.POST("/:id/translation/:lang/status", localeHandler.ChangeStatus)
+	localeItemGroup.POST("/:id/context", localeHandler.ChangeContext)
+	localeItemGroup.POST("/:id/reference", localeHandler.ChangeReferenceLang)
+	localeItemGroup.POST("/:id/length-limit", localeHandler.ChangeLengthLimit)
+	localeItemGroup.POST("/:id/description", localeHandler.ChangeDescription)
+	localeItemGroup.POST("/:id/attachments", localeHandler.AddAttachment)
+	localeItemGroup.DELETE("/:id/attachments/:attachmentId", localeHandler.RemoveAttachment)
+	localeItemGroup.POST("/:id/tags", localeHandler.AddTag)
+	localeItemGroup.DELETE("/:id/tags/:tag", localeHandler.RemoveTag)
+
+	glossaryGroup := apiGroup.Group("/glossary")
+	glossaryGroup.Use(userHandler.SessionValidator())
+	glossaryGroup.GET("", glossaryHandler.GetTerms)
+	glossaryGroup.POST("", glossaryHandler.AddTerm, userHandler.AdminValidator())
+	glossaryGroup.PUT("/:id", glossaryHandler.UpdateTerm, userHandler.AdminValidator())
+	glossaryGroup.DELETE("/:id", glossaryHandler.DeleteTerm, userHandler.AdminValidator())
+
+	contextsGroup := apiGroup.Group("/contexts")
+	contextsGroup.Use(userHandler.SessionValidator())
+	contextsGroup.GET("", contextHandler.GetContexts)
+	contextsGroup.POST("", contextHandler.CreateContext)
+	contextsGroup.GET("/tree", contextHandler.GetTree)
+	contextsGroup.GET("/:id", contextHandler.GetContextSettings)
+	contextsGroup.DELETE("/:id", contextHandler.ArchiveContext)
+	contextsGroup.POST("/:id/rename", contextHandler.RenameContext)
+	contextsGroup.POST("/:id/description", contextHandler.DescribeContext)
+	contextsGroup.POST("/:id/required-langs", contextHandler.SetRequiredLangs)
+	contextsGroup.POST("/:id/reference", contextHandler.SetDefaultReferenceLang)
+
+	langGroup := apiGroup.Group("/lang")
+	langGroup.Use(userHandler.SessionValidator())
+	langGroup.GET("", langHandler.GetLangs)
+	langGroup.GET("/:code", langHandler.GetLang)
+	langGroup.POST("", langHandler.RegisterLang, userHandler.AdminValidator())
+	langGroup.PUT("/:code", langHandler.UpdateLang, userHandler.AdminValidator())
+	langGroup.POST("/:code/enable", langHandler.EnableLang, userHandler.AdminValidator())
+	langGroup.POST("/:code/disable", langHandler.DisableLang, userHandler.AdminValidator())
 
 	apiGroup.GET("/login", userHandler.Login)
 	apiGroup.GET("/auth-callback", userHandler.AuthCallback)
@@ -0,0 +1,104 @@
+// Package blob stores binary files, like locale item screenshots, by name
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrBlobNotFound     = errors.New("blob not found")
+	ErrInvalidBlobName  = errors.New("invalid blob name")
+	ErrUnknownBlobStore = errors.New("unknown blob store")
+)
+
+// Store is a blob storage; names can contain '/' to group blobs
+type Store interface {
+	Put(name string, content io.Reader) error
+	Get(name string) (io.ReadCloser, error)
+	Delete(name string) error
+}
+
+const LocalBlobStoreFolder = "./attachments-bucket"
+
+// NewStore returns the store selected by BLOB_STORE env var; local file system is the default
+func NewStore() (Store, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		folder := os.Getenv("BLOB_LOCAL_FOLDER")
+		if folder == "" {
+			folder = LocalBlobStoreFolder
+		}
+		return &LocalStoreOnFile{Folder: folder}, nil
+	}
+	return nil, ErrUnknownBlobStore
+}
+
+// LocalStoreOnFile is the implementation of Store on local file system: every blob is a file in Folder
+type LocalStoreOnFile struct {
+	Folder string
+}
+
+func (store *LocalStoreOnFile) path(name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", ErrInvalidBlobName
+	}
+	return filepath.Join(store.Folder, filepath.FromSlash(name)), nil
+}
+
+func (store *LocalStoreOnFile) Put(name string, content io.Reader) error {
+	path, err := store.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("error on create local folder for blobs: %s", err.Error())
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, content)
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+func (store *LocalStoreOnFile) Get(name string) (io.ReadCloser, error) {
+	path, err := store.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (store *LocalStoreOnFile) Delete(name string) error {
+	path, err := store.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrBlobNotFound
+	}
+	return err
+}
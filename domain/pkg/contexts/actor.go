@@ -0,0 +1,281 @@
+package contexts
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/eventstore-go-v2/pkg/store"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+// ContextActorState rebuilds the context aggregates on event store notifies and persists them in
+// the contexts projection
+type ContextActorState struct {
+	store      *store.EventStore
+	repository *sqlx.DB
+}
+
+func newContextActorState() (*ContextActorState, error) {
+	es, err := store.NewEventStore([]store.EventStoreConfigurator{store.WithPostgresqlRepository})
+	if err != nil {
+		return nil, err
+	}
+	repo, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &ContextActorState{
+		store:      &es,
+		repository: repo,
+	}, nil
+}
+
+var ContextActorAddress = actor.NewAddress("locale", "context-actor")
+
+func NewContextActor() (*actor.Actor, error) {
+	state, err := newContextActorState()
+	if err != nil {
+		return nil, err
+	}
+	a, err := actor.NewActor(ContextActorAddress, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// subscribe event store notifies
+	addSubMsg := actor.NewAddSubcriptionMessage(a.GetAddress(), store.EventStoreAddress)
+	err = actor.SendMessage(addSubMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+var insertUpdateContext string = `--insert sql
+INSERT INTO
+locale.contexts (
+	context_id,
+	name,
+	moving_from,
+	parent,
+	description,
+	required_langs,
+	default_reference_lang,
+	is_archived,
+	version,
+	updated_at,
+	updated_by
+)
+VALUES (
+	:context_id,
+	:name,
+	:moving_from,
+	:parent,
+	:description,
+	:required_langs,
+	:default_reference_lang,
+	:is_archived,
+	:version,
+	:updated_at,
+	:updated_by
+)
+ON CONFLICT (context_id)
+DO UPDATE SET
+    name = :name,
+    moving_from = :moving_from,
+    parent = :parent,
+    description = :description,
+    required_langs = :required_langs,
+    default_reference_lang = :default_reference_lang,
+    is_archived = :is_archived,
+    version = :version,
+    updated_at = :updated_at,
+    updated_by = :updated_by;
+`
+
+// updateContext rebuilds the context aggregate with aggregateID and persists it;
+// events of other aggregates are ignored
+func (state *ContextActorState) updateContext(aggregateID string) error {
+	if !strings.HasPrefix(aggregateID, ContextAggregateName+"-") {
+		return nil
+	}
+
+	evts, _, err := state.store.Repository.RetriveByAggregateID(aggregateID)
+	if err != nil {
+		return err
+	}
+	if len(evts) == 0 || evts[0].AggregateName != ContextAggregateName {
+		return nil
+	}
+
+	c := Context{}
+	err = c.Reduce(evts)
+	if err != nil {
+		return err
+	}
+
+	_, err = state.repository.NamedExec(insertUpdateContext, c)
+	return err
+}
+
+func (state *ContextActorState) getContexts(params RetriveContextsMessageBody) ([]Context, error) {
+	result := make([]Context, 0)
+	query := "SELECT * FROM locale.contexts WHERE true"
+	args := make([]any, 0)
+	if !params.IncludeArchived {
+		query += " AND is_archived = false"
+	}
+	if params.Parent != nil {
+		args = append(args, *params.Parent)
+		query += fmt.Sprintf(" AND parent = $%d", len(args))
+	}
+	query += " ORDER BY name;"
+
+	err := state.repository.Select(&result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (state *ContextActorState) getContext(id string, name string) (Context, error) {
+	result := Context{}
+	query := "SELECT * FROM locale.contexts WHERE context_id = $1;"
+	arg := id
+	if id == "" {
+		query = "SELECT * FROM locale.contexts WHERE name = $1;"
+		arg = name
+	}
+
+	err := state.repository.Get(&result, query, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrContextNotFound
+	}
+	return result, err
+}
+
+const (
+	nameReserve    = `INSERT INTO locale.context_names (name, context_id) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING`
+	nameReservedBy = `SELECT context_id FROM locale.context_names WHERE name = $1`
+	nameRelease    = `DELETE FROM locale.context_names WHERE name = $1 AND context_id = $2`
+)
+
+// reserveName reserves name for the context with id; ErrContextNameUsed if reserved by another context
+func (state *ContextActorState) reserveName(id string, name string) error {
+	_, err := state.repository.Exec(nameReserve, name, id)
+	if err != nil {
+		return err
+	}
+	var reservedBy string
+	err = state.repository.Get(&reservedBy, nameReservedBy, name)
+	if err != nil {
+		return err
+	}
+	if reservedBy != id {
+		return ErrContextNameUsed
+	}
+	return nil
+}
+
+// releaseName releases name if reserved by the context with id
+func (state *ContextActorState) releaseName(id string, name string) error {
+	_, err := state.repository.Exec(nameRelease, name, id)
+	return err
+}
+
+// RetriveContextsMessageBody is the query message for the contexts, archived ones only if requested;
+// Parent, if set, selects its direct children, the root contexts if empty
+type RetriveContextsMessageBody struct {
+	IncludeArchived bool
+	Parent          *string
+}
+
+type RetriveContextsMessageBodyResult struct {
+	Contexts []Context
+}
+
+// RetriveContextMessageBody is the query message for the context with Id or, if not set, with Name;
+// ErrContextNotFound if missing
+type RetriveContextMessageBody struct {
+	Id   string
+	Name string
+}
+
+type RetriveContextMessageBodyResult struct {
+	Context Context
+}
+
+// ReserveContextNameMessageBody is the command message to reserve Name for the context with Id before
+// storing the event that gives it; reserving a name again for the same context succeeds, so a failed
+// command can be repeated. ErrContextNameUsed if reserved by another context
+type ReserveContextNameMessageBody struct {
+	Id   string
+	Name string
+}
+
+// ReleaseContextNameMessageBody is the command message to release Name if reserved by the context
+// with Id: when it is no more used after a rename or if the event giving it is not stored
+type ReleaseContextNameMessageBody struct {
+	Id   string
+	Name string
+}
+
+// ContextNameMessageBodyResult is the result of name reservation messages
+type ContextNameMessageBodyResult struct{}
+
+func (state *ContextActorState) Process(msg actor.Message) {
+	switch payload := msg.Body.(type) {
+	case store.StoreEventAddedBody:
+		err := state.updateContext(payload.AggregateID)
+		if err != nil {
+			slog.Error("error on update contexts", slog.String("aggregateId", payload.AggregateID), slog.String("err", err.Error()))
+		}
+
+	case RetriveContextsMessageBody:
+		result, err := state.getContexts(payload)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(RetriveContextsMessageBodyResult{result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case ReserveContextNameMessageBody:
+		err := state.reserveName(payload.Id, payload.Name)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(ContextNameMessageBodyResult{}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case ReleaseContextNameMessageBody:
+		err := state.releaseName(payload.Id, payload.Name)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(ContextNameMessageBodyResult{}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case RetriveContextMessageBody:
+		result, err := state.getContext(payload.Id, payload.Name)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(RetriveContextMessageBodyResult{result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	}
+}
+
+func (state *ContextActorState) GetState() any {
+	return nil
+}
+
+func (state *ContextActorState) Shutdown() {
+	err := state.repository.Close()
+	if err != nil {
+		slog.Error("error closing database connection", slog.String("err", err.Error()))
+	}
+	state.repository = nil
+	state.store = nil
+}
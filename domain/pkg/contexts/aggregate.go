@@ -0,0 +1,82 @@
+package contexts
+
+import (
+	"fmt"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/eventstore-go-v2/pkg/utils"
+)
+
+// Reduce applies events in order and stops on the first one that can not be decoded
+func (c *Context) Reduce(evts []events.StoreEvent) error {
+	for _, evt := range evts {
+		if err := c.Apply(evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Context) Apply(evt events.StoreEvent) error {
+	// version is the number of events in aggregate stream
+	c.Version++
+	c.UpdatedAt = evt.CreatedAt
+	c.UpdatedBy = evt.CreatedBy
+
+	switch evt.EventType {
+	case CreateContextStoreEventType:
+		payload, err := decodePayload[CreateContextPayload](evt)
+		if err != nil {
+			return err
+		}
+		c.Id = payload.Id
+		c.Name = payload.Name
+		c.Parent = ParentName(payload.Name)
+		c.Description = payload.Description
+		c.RequiredLangs = payload.RequiredLangs
+		c.DefaultReferenceLang = payload.DefaultReferenceLang
+	case RenameContextStoreEventType:
+		payload, err := decodePayload[RenameContextPayload](evt)
+		if err != nil {
+			return err
+		}
+		// items stay in the first name until moved, also if renamed again in between
+		if c.MovingFrom == "" {
+			c.MovingFrom = c.Name
+		}
+		c.Name = payload.Name
+		c.Parent = ParentName(payload.Name)
+	case ItemsMovedContextStoreEventType:
+		c.MovingFrom = ""
+	case DescribeContextStoreEventType:
+		payload, err := decodePayload[DescribeContextPayload](evt)
+		if err != nil {
+			return err
+		}
+		c.Description = payload.Description
+	case ArchiveContextStoreEventType:
+		c.IsArchived = true
+	case SetRequiredLangsStoreEventType:
+		payload, err := decodePayload[SetRequiredLangsPayload](evt)
+		if err != nil {
+			return err
+		}
+		c.RequiredLangs = payload.Langs
+	case SetDefaultReferenceLangStoreEventType:
+		payload, err := decodePayload[SetDefaultReferenceLangPayload](evt)
+		if err != nil {
+			return err
+		}
+		c.DefaultReferenceLang = payload.Lang
+	}
+	return nil
+}
+
+func decodePayload[T any](evt events.StoreEvent) (T, error) {
+	var result T
+	payload, err := utils.DecodePayload[T](evt.PayloadData)
+	if err != nil {
+		return result, fmt.Errorf("error on decode payload %s: %w", evt.EventType, err)
+	}
+	return *payload, nil
+}
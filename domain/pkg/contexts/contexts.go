@@ -0,0 +1,105 @@
+// Package contexts manages the contexts grouping locale items: every context is an event sourced
+// aggregate with its settings, identified by an id so that it can be renamed
+package contexts
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidName     = errors.New("context name must be set, at most 64 characters and without empty path segments")
+	ErrContextNotFound = errors.New("context not found")
+	ErrContextNameUsed = errors.New("context name already used")
+)
+
+// MaxNameLength is the max length of a context name, as stored on locale items
+const MaxNameLength = 64
+
+// PathSeparator separates the segments of a context name: checkout/payment/card is a child
+// of checkout/payment
+const PathSeparator = "/"
+
+// NormalizeName returns name without spaces around its path segments if valid
+func NormalizeName(name string) (string, error) {
+	segments := strings.Split(name, PathSeparator)
+	for i, segment := range segments {
+		segments[i] = strings.TrimSpace(segment)
+		if segments[i] == "" {
+			return "", ErrInvalidName
+		}
+	}
+
+	name = strings.Join(segments, PathSeparator)
+	if len(name) > MaxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// ParentName returns the name of the parent context of name; root contexts have an empty parent
+func ParentName(name string) string {
+	i := strings.LastIndex(name, PathSeparator)
+	if i < 0 {
+		return ""
+	}
+	return name[:i]
+}
+
+// InSubtree reports if name is root or one of its descendants; every name is in the subtree of
+// an empty root
+func InSubtree(name string, root string) bool {
+	return root == "" || name == root || strings.HasPrefix(name, root+PathSeparator)
+}
+
+// Langs are the lang codes required by a context
+type Langs []string
+
+// Value implements driver.Valuer to persist langs as json
+func (langs Langs) Value() (driver.Value, error) {
+	if langs == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(langs))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner to read langs from json
+func (langs *Langs) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*langs = Langs{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type %T for langs", src)
+	}
+	return json.Unmarshal(data, langs)
+}
+
+// Context is the aggregate of a context: Name is the one set on its locale items; MovingFrom is the
+// name its items are moved from after a rename, empty once all are moved
+type Context struct {
+	Id                   string    `db:"context_id" json:"id"`
+	Name                 string    `db:"name" json:"name"`
+	MovingFrom           string    `db:"moving_from" json:"movingFrom"`
+	Parent               string    `db:"parent" json:"parent"`
+	Description          string    `db:"description" json:"description"`
+	RequiredLangs        Langs     `db:"required_langs" json:"requiredLangs"`
+	DefaultReferenceLang string    `db:"default_reference_lang" json:"defaultReferenceLang"`
+	IsArchived           bool      `db:"is_archived" json:"isArchived"`
+	Version              int       `db:"version" json:"version"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updatedAt"`
+	UpdatedBy            string    `db:"updated_by" json:"updatedBy"`
+}
@@ -0,0 +1,141 @@
+package contexts
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+)
+
+const ContextAggregateName = "context"
+
+// contextSchemaVersion is the payload schema version of context events
+const contextSchemaVersion = 1
+
+// AggregateID returns the aggregate id of the context with id
+func AggregateID(id string) string {
+	return ContextAggregateName + "-" + id
+}
+
+// ID returns the id of the context with aggregateID
+func ID(aggregateID string) string {
+	return strings.TrimPrefix(aggregateID, ContextAggregateName+"-")
+}
+
+const CreateContextStoreEventType = "created-context"
+
+type CreateContextPayload struct {
+	SchemaVersion        int
+	Id                   string
+	Name                 string
+	Description          string
+	RequiredLangs        Langs
+	DefaultReferenceLang string
+}
+
+// NewCreateEvent creates a context with a new id; langs must be canonical codes
+func NewCreateEvent(name string, description string, requiredLangs []string, defaultReferenceLang string, userID string) (events.StoreEvent, error) {
+	name, err := NormalizeName(name)
+	if err != nil {
+		return events.StoreEvent{}, err
+	}
+	id := uuid.NewString()
+	if requiredLangs == nil {
+		requiredLangs = make([]string, 0)
+	}
+
+	payload := CreateContextPayload{
+		SchemaVersion:        contextSchemaVersion,
+		Id:                   id,
+		Name:                 name,
+		Description:          description,
+		RequiredLangs:        requiredLangs,
+		DefaultReferenceLang: defaultReferenceLang,
+	}
+
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(CreateContextStoreEventType, ContextAggregateName, userID, payload, &aggregateID)
+}
+
+const RenameContextStoreEventType = "context-renamed"
+
+type RenameContextPayload struct {
+	SchemaVersion int
+	Name          string
+}
+
+func NewRenameEvent(id string, name string, userID string) (events.StoreEvent, error) {
+	name, err := NormalizeName(name)
+	if err != nil {
+		return events.StoreEvent{}, err
+	}
+
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(RenameContextStoreEventType, ContextAggregateName, userID, RenameContextPayload{contextSchemaVersion, name}, &aggregateID)
+}
+
+const ItemsMovedContextStoreEventType = "context-items-moved"
+
+type ItemsMovedContextPayload struct {
+	SchemaVersion int
+}
+
+// NewItemsMovedEvent completes the rename of a context once all its items are moved to the new name
+func NewItemsMovedEvent(id string, userID string) (events.StoreEvent, error) {
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(ItemsMovedContextStoreEventType, ContextAggregateName, userID, ItemsMovedContextPayload{contextSchemaVersion}, &aggregateID)
+}
+
+const DescribeContextStoreEventType = "context-described"
+
+type DescribeContextPayload struct {
+	SchemaVersion int
+	Description   string
+}
+
+func NewDescribeEvent(id string, description string, userID string) (events.StoreEvent, error) {
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(DescribeContextStoreEventType, ContextAggregateName, userID, DescribeContextPayload{contextSchemaVersion, description}, &aggregateID)
+}
+
+const ArchiveContextStoreEventType = "archived-context"
+
+type ArchiveContextPayload struct {
+	SchemaVersion int
+}
+
+func NewArchiveEvent(id string, userID string) (events.StoreEvent, error) {
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(ArchiveContextStoreEventType, ContextAggregateName, userID, ArchiveContextPayload{contextSchemaVersion}, &aggregateID)
+}
+
+const SetRequiredLangsStoreEventType = "context-required-langs-set"
+
+type SetRequiredLangsPayload struct {
+	SchemaVersion int
+	Langs         Langs
+}
+
+// NewSetRequiredLangsEvent replaces the required langs of context; langs must be canonical codes
+func NewSetRequiredLangsEvent(id string, langs []string, userID string) (events.StoreEvent, error) {
+	if langs == nil {
+		langs = make([]string, 0)
+	}
+
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(SetRequiredLangsStoreEventType, ContextAggregateName, userID, SetRequiredLangsPayload{contextSchemaVersion, langs}, &aggregateID)
+}
+
+const SetDefaultReferenceLangStoreEventType = "context-default-reference-lang-set"
+
+type SetDefaultReferenceLangPayload struct {
+	SchemaVersion int
+	Lang          string
+}
+
+// NewSetDefaultReferenceLangEvent sets the reference lang of new items created without lang;
+// an empty lang removes it
+func NewSetDefaultReferenceLangEvent(id string, lang string, userID string) (events.StoreEvent, error) {
+	aggregateID := AggregateID(id)
+	return events.NewStoreEvent(SetDefaultReferenceLangStoreEventType, ContextAggregateName, userID, SetDefaultReferenceLangPayload{contextSchemaVersion, lang}, &aggregateID)
+}
@@ -0,0 +1,104 @@
+package contexts
+
+import (
+	"slices"
+	"strings"
+)
+
+// Count is the number of translations in Lang of the items in Context; Items counts the ones
+// having Lang as reference
+type Count struct {
+	Context      string `db:"context"`
+	Lang         string `db:"lang"`
+	Items        int    `db:"items"`
+	Translations int    `db:"translations"`
+}
+
+// Counts are the items of a context with their translations, in total and by lang
+type Counts struct {
+	Items        int            `json:"items"`
+	Translations int            `json:"translations"`
+	Langs        map[string]int `json:"langs"`
+}
+
+func newCounts() Counts {
+	return Counts{Langs: make(map[string]int)}
+}
+
+func (counts *Counts) add(c Count) {
+	counts.Items += c.Items
+	counts.Translations += c.Translations
+	counts.Langs[c.Lang] += c.Translations
+}
+
+// Node is a context of a tree: Counts are the ones of its own items, Total sums the ones of
+// its subtree
+type Node struct {
+	Name     string  `json:"name"`
+	Segment  string  `json:"segment"`
+	Counts   Counts  `json:"counts"`
+	Total    Counts  `json:"total"`
+	Children []*Node `json:"children"`
+}
+
+func newNode(name string) *Node {
+	return &Node{
+		Name:     name,
+		Segment:  name[strings.LastIndex(name, PathSeparator)+1:],
+		Counts:   newCounts(),
+		Total:    newCounts(),
+		Children: make([]*Node, 0),
+	}
+}
+
+// NewTree returns the subtree of root with the counts of its contexts; names are contexts to
+// include without items. Missing intermediate contexts are added, as checkout for checkout/payment;
+// an empty root is the whole tree
+func NewTree(root string, counts []Count, names []string) *Node {
+	rootNode := newNode(root)
+	nodes := map[string]*Node{root: rootNode}
+
+	var get func(name string) *Node
+	get = func(name string) *Node {
+		if node, ok := nodes[name]; ok {
+			return node
+		}
+		node := newNode(name)
+		nodes[name] = node
+		parent := get(ParentName(name))
+		parent.Children = append(parent.Children, node)
+		return node
+	}
+
+	for _, name := range names {
+		if InSubtree(name, root) {
+			get(name)
+		}
+	}
+
+	for _, c := range counts {
+		if !InSubtree(c.Context, root) {
+			continue
+		}
+		node := get(c.Context)
+		node.Counts.add(c)
+		for name := c.Context; ; name = ParentName(name) {
+			nodes[name].Total.add(c)
+			if name == root {
+				break
+			}
+		}
+	}
+
+	sortChildren(rootNode)
+	return rootNode
+}
+
+func sortChildren(node *Node) {
+	slices.SortFunc(node.Children, func(a, b *Node) int {
+		return strings.Compare(a.Segment, b.Segment)
+	})
+	for _, child := range node.Children {
+		sortChildren(child)
+	}
+}
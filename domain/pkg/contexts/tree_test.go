@@ -0,0 +1,134 @@
+package contexts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// flatten returns a line for every node of the tree, depth first: name, its own items/translations
+// and the total ones of its subtree
+func flatten(node *Node) []string {
+	result := []string{fmt.Sprintf("%s %d/%d %d/%d", node.Name, node.Counts.Items, node.Counts.Translations, node.Total.Items, node.Total.Translations)}
+	for _, child := range node.Children {
+		result = append(result, flatten(child)...)
+	}
+	return result
+}
+
+func TestNewTree(t *testing.T) {
+	tests := []struct {
+		name   string
+		root   string
+		counts []Count
+		names  []string
+		want   []string
+	}{
+		{
+			name: "empty",
+			want: []string{" 0/0 0/0"},
+		},
+		{
+			name:   "missing intermediate contexts",
+			counts: []Count{{Context: "checkout/payment/card", Lang: "en", Items: 1, Translations: 1}},
+			want: []string{
+				" 0/0 1/1",
+				"checkout 0/0 1/1",
+				"checkout/payment 0/0 1/1",
+				"checkout/payment/card 1/1 1/1",
+			},
+		},
+		{
+			name: "totals of subtree",
+			counts: []Count{
+				{Context: "checkout", Lang: "en", Items: 2, Translations: 2},
+				{Context: "checkout", Lang: "it", Translations: 1},
+				{Context: "checkout/payment", Lang: "en", Items: 1, Translations: 1},
+				{Context: "home", Lang: "en", Items: 3, Translations: 3},
+			},
+			want: []string{
+				" 0/0 6/7",
+				"checkout 2/3 3/4",
+				"checkout/payment 1/1 1/1",
+				"home 3/3 3/3",
+			},
+		},
+		{
+			name:   "contexts without items",
+			counts: []Count{{Context: "home", Lang: "en", Items: 1, Translations: 1}},
+			names:  []string{"settings/privacy", "home"},
+			want: []string{
+				" 0/0 1/1",
+				"home 1/1 1/1",
+				"settings 0/0 0/0",
+				"settings/privacy 0/0 0/0",
+			},
+		},
+		{
+			name: "subtree of root",
+			root: "checkout",
+			counts: []Count{
+				{Context: "checkout", Lang: "en", Items: 1, Translations: 1},
+				{Context: "checkout/payment", Lang: "en", Items: 1, Translations: 1},
+				{Context: "checkouts", Lang: "en", Items: 1, Translations: 1},
+				{Context: "home", Lang: "en", Items: 1, Translations: 1},
+			},
+			names: []string{"checkout/shipping", "home/banner"},
+			want: []string{
+				"checkout 1/1 2/2",
+				"checkout/payment 1/1 1/1",
+				"checkout/shipping 0/0 0/0",
+			},
+		},
+		{
+			name:   "root without items",
+			root:   "checkout",
+			counts: []Count{{Context: "home", Lang: "en", Items: 1, Translations: 1}},
+			want:   []string{"checkout 0/0 0/0"},
+		},
+		{
+			name: "children sorted by segment",
+			counts: []Count{
+				{Context: "b", Lang: "en", Items: 1, Translations: 1},
+				{Context: "a/z", Lang: "en", Items: 1, Translations: 1},
+				{Context: "a/m", Lang: "en", Items: 1, Translations: 1},
+			},
+			want: []string{
+				" 0/0 3/3",
+				"a 0/0 2/2",
+				"a/m 1/1 1/1",
+				"a/z 1/1 1/1",
+				"b 1/1 1/1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := flatten(NewTree(tt.root, tt.counts, tt.names))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestNewTreeLangs(t *testing.T) {
+	counts := []Count{
+		{Context: "checkout", Lang: "en", Items: 2, Translations: 2},
+		{Context: "checkout", Lang: "it", Translations: 1},
+		{Context: "checkout/payment", Lang: "it", Items: 1, Translations: 1},
+	}
+
+	tree := NewTree("checkout", counts, nil)
+	if want := map[string]int{"en": 2, "it": 1}; !reflect.DeepEqual(tree.Counts.Langs, want) {
+		t.Errorf("got counts %v, want %v", tree.Counts.Langs, want)
+	}
+	if want := map[string]int{"en": 2, "it": 2}; !reflect.DeepEqual(tree.Total.Langs, want) {
+		t.Errorf("got total %v, want %v", tree.Total.Langs, want)
+	}
+	if got := tree.Children[0].Segment; got != "payment" {
+		t.Errorf("got segment %q, want %q", got, "payment")
+	}
+}
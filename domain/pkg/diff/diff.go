@@ -0,0 +1,71 @@
+package diff
+
+import "strings"
+
+// operation types of a diff
+const (
+	Equal  = "equal"
+	Insert = "insert"
+	Delete = "delete"
+)
+
+// Op is a run of words kept, added or removed
+type Op struct {
+	Type string
+	Text string
+}
+
+// Words returns the word level diff to turn old text in new text; words are whitespace separated
+func Words(old string, new string) []Op {
+	a := strings.Fields(old)
+	b := strings.Fields(new)
+
+	// lcs[i][j] is the longest common subsequence length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	result := make([]Op, 0)
+	add := func(opType string, word string) {
+		last := len(result) - 1
+		if last >= 0 && result[last].Type == opType {
+			result[last].Text += " " + word
+			return
+		}
+		result = append(result, Op{Type: opType, Text: word})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(Equal, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(Delete, a[i])
+			i++
+		default:
+			add(Insert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(Delete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(Insert, b[j])
+	}
+
+	return result
+}
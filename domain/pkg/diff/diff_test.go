@@ -0,0 +1,38 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []Op
+	}{
+		{"both empty", "", "", []Op{}},
+		{"same text", "hello world", "hello world", []Op{{Equal, "hello world"}}},
+		{"whitespace only changes", " hello\t world\n", "hello world", []Op{{Equal, "hello world"}}},
+		{"from empty", "", "hello world", []Op{{Insert, "hello world"}}},
+		{"to empty", "hello world", "", []Op{{Delete, "hello world"}}},
+		{"word replaced", "the red car", "the blue car", []Op{{Equal, "the"}, {Delete, "red"}, {Insert, "blue"}, {Equal, "car"}}},
+		{"word inserted", "save file", "save the file", []Op{{Equal, "save"}, {Insert, "the"}, {Equal, "file"}}},
+		{"word deleted", "save the file", "save file", []Op{{Equal, "save"}, {Delete, "the"}, {Equal, "file"}}},
+		{"appended", "save", "save all files", []Op{{Equal, "save"}, {Insert, "all files"}}},
+		{"prefix removed", "please save now", "save now", []Op{{Delete, "please"}, {Equal, "save now"}}},
+		{"all replaced", "a b", "c d", []Op{{Delete, "a b"}, {Insert, "c d"}}},
+		{"moved word", "a b c", "b c a", []Op{{Delete, "a"}, {Equal, "b c"}, {Insert, "a"}}},
+		{"repeated words", "a a b", "a b b", []Op{{Equal, "a"}, {Delete, "a"}, {Equal, "b"}, {Insert, "b"}}},
+		{"case sensitive", "Save", "save", []Op{{Delete, "Save"}, {Insert, "save"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Words(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package glossary
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+var (
+	ErrTermNotFound = errors.New("glossary term not found")
+)
+
+type GlossaryActorState struct {
+	repository *sqlx.DB
+	matcher    *Matcher
+}
+
+func newGlossaryActorState() (*GlossaryActorState, error) {
+	repo, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &GlossaryActorState{
+		repository: repo,
+		matcher:    NewMatcher(),
+	}, nil
+}
+
+var GlossaryActorAddress = actor.NewAddress("locale", "glossary-actor")
+
+func NewGlossaryActor() (*actor.Actor, error) {
+	state, err := newGlossaryActorState()
+	if err != nil {
+		return nil, err
+	}
+	a, err := actor.NewActor(GlossaryActorAddress, state)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+var insertTerm string = `--insert sql
+INSERT INTO
+locale.glossary_term (
+	term_id,
+	term,
+	lang,
+	case_sensitive,
+	forbidden,
+	translations,
+	notes,
+	updated_at,
+	updated_by
+)
+VALUES (
+	:term_id,
+	:term,
+	:lang,
+	:case_sensitive,
+	:forbidden,
+	:translations,
+	:notes,
+	:updated_at,
+	:updated_by
+);
+`
+
+var updateTerm string = `--update sql
+UPDATE locale.glossary_term SET
+    term = :term,
+    lang = :lang,
+    case_sensitive = :case_sensitive,
+    forbidden = :forbidden,
+    translations = :translations,
+    notes = :notes,
+    updated_at = :updated_at,
+    updated_by = :updated_by
+WHERE term_id = :term_id;
+`
+
+// saveTerm inserts or updates term: updating a missing term returns ErrTermNotFound
+func (state GlossaryActorState) saveTerm(term Term, isNew bool) error {
+	query := updateTerm
+	if isNew {
+		query = insertTerm
+	}
+	result, err := state.repository.NamedExec(query, term)
+	if err != nil {
+		return err
+	}
+
+	numRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if numRows != 1 {
+		return ErrTermNotFound
+	}
+
+	return nil
+}
+
+func (state GlossaryActorState) deleteTerm(id string) error {
+	result, err := state.repository.Exec("DELETE FROM locale.glossary_term WHERE term_id = $1;", id)
+	if err != nil {
+		return err
+	}
+
+	numRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if numRows != 1 {
+		return ErrTermNotFound
+	}
+
+	return nil
+}
+
+func (state GlossaryActorState) getTerms(lang string) ([]Term, error) {
+	result := make([]Term, 0)
+	query := "SELECT * FROM locale.glossary_term"
+	args := make([]any, 0)
+	if lang != "" {
+		query += " WHERE lang = $1"
+		args = append(args, lang)
+	}
+	query += " ORDER BY lower(term);"
+
+	err := state.repository.Select(&result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getTermsToCheck returns the terms relevant for a translation: the ones of reference lang or lang
+// and all forbidden terms, that can have translations in lang
+func (state GlossaryActorState) getTermsToCheck(referenceLang string, lang string) ([]Term, error) {
+	result := make([]Term, 0)
+	err := state.repository.Select(&result, "SELECT * FROM locale.glossary_term WHERE lang = $1 OR lang = $2 OR forbidden = true;", referenceLang, lang)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SaveTermMessageBody is the command message to add a term or, if IsNew is false, to update it
+type SaveTermMessageBody struct {
+	Term  Term
+	IsNew bool
+}
+
+type SaveTermMessageBodyResult struct {
+	Success bool
+}
+
+type DeleteTermMessageBody struct {
+	Id string
+}
+
+type DeleteTermMessageBodyResult struct {
+	Success bool
+}
+
+// RetriveTermsMessageBody is the query message for the glossary terms; Lang is optional
+type RetriveTermsMessageBody struct {
+	Lang string
+}
+
+type RetriveTermsMessageBodyResult struct {
+	Terms []Term
+}
+
+// CheckMessageBody is the query message to check content in lang against the glossary terms found
+// in the reference content
+type CheckMessageBody struct {
+	ReferenceLang    string
+	ReferenceContent string
+	Lang             string
+	Content          string
+}
+
+type CheckMessageBodyResult struct {
+	Violations []Violation
+}
+
+func (state *GlossaryActorState) Process(msg actor.Message) {
+	switch payload := msg.Body.(type) {
+	case SaveTermMessageBody:
+		err := state.saveTerm(payload.Term, payload.IsNew)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(SaveTermMessageBodyResult{err == nil}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case DeleteTermMessageBody:
+		err := state.deleteTerm(payload.Id)
+		if err == nil {
+			state.matcher.Forget(payload.Id)
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(DeleteTermMessageBodyResult{err == nil}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case RetriveTermsMessageBody:
+		terms, err := state.getTerms(payload.Lang)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(RetriveTermsMessageBodyResult{terms}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case CheckMessageBody:
+		violations := make([]Violation, 0)
+		terms, err := state.getTermsToCheck(payload.ReferenceLang, payload.Lang)
+		if err == nil {
+			violations = state.matcher.Check(terms, payload.ReferenceLang, payload.ReferenceContent, payload.Lang, payload.Content)
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(CheckMessageBodyResult{violations}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	}
+}
+
+func (state *GlossaryActorState) GetState() any {
+	return nil
+}
+
+func (state *GlossaryActorState) Shutdown() {
+	err := state.repository.Close()
+	if err != nil {
+		slog.Error("error closing database connection", slog.String("err", err.Error()))
+	}
+	state.repository = nil
+}
@@ -0,0 +1,181 @@
+package glossary
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTermRequired = errors.New("term and lang are required")
+)
+
+// Translations are the approved translations of a term by lang
+type Translations map[string]string
+
+// Value implements driver.Valuer to store translations as json
+func (translations Translations) Value() (driver.Value, error) {
+	if translations == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(translations)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner to read translations from json
+func (translations *Translations) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*translations = Translations{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type %T for glossary translations", src)
+	}
+	return json.Unmarshal(data, translations)
+}
+
+// Term is a source term in Lang with its approved translations; a forbidden term and its
+// translations must not be used at all
+type Term struct {
+	Id            string       `db:"term_id" json:"id"`
+	Term          string       `db:"term" json:"term"`
+	Lang          string       `db:"lang" json:"lang"`
+	CaseSensitive bool         `db:"case_sensitive" json:"caseSensitive"`
+	Forbidden     bool         `db:"forbidden" json:"forbidden"`
+	Translations  Translations `db:"translations" json:"translations"`
+	Notes         string       `db:"notes" json:"notes"`
+	UpdatedAt     time.Time    `db:"updated_at" json:"updatedAt"`
+	UpdatedBy     string       `db:"updated_by" json:"updatedBy"`
+}
+
+// NewTerm returns a term with a new id
+func NewTerm(term string, lang string, caseSensitive bool, forbidden bool, translations Translations, notes string, userID string) (Term, error) {
+	if term == "" || lang == "" {
+		return Term{}, ErrTermRequired
+	}
+
+	if translations == nil {
+		translations = Translations{}
+	}
+
+	return Term{
+		uuid.NewString(),
+		term,
+		lang,
+		caseSensitive,
+		forbidden,
+		translations,
+		notes,
+		time.Now().UTC(),
+		userID,
+	}, nil
+}
+
+// Violation is a glossary term not respected by a translation: Found is the forbidden word used,
+// Expected the approved translation missing
+type Violation struct {
+	TermId    string `json:"termId"`
+	Term      string `json:"term"`
+	Forbidden bool   `json:"forbidden"`
+	Found     string `json:"found,omitempty"`
+	Expected  string `json:"expected,omitempty"`
+}
+
+// termPatterns are the whole word patterns of a term and of its translations by lang, compiled
+// at the term update time
+type termPatterns struct {
+	updatedAt    time.Time
+	term         *regexp.Regexp
+	translations map[string]*regexp.Regexp
+}
+
+func compileTerm(t Term) termPatterns {
+	result := termPatterns{
+		updatedAt:    t.UpdatedAt,
+		term:         wordPattern(t.Term, t.CaseSensitive),
+		translations: make(map[string]*regexp.Regexp, len(t.Translations)),
+	}
+	for lang, translation := range t.Translations {
+		if translation != "" {
+			result.translations[lang] = wordPattern(translation, t.CaseSensitive)
+		}
+	}
+	return result
+}
+
+// Matcher checks translations against glossary terms compiling the patterns of each term once, and
+// again when the term is updated; it is not safe for concurrent use
+type Matcher struct {
+	patterns map[string]termPatterns
+}
+
+func NewMatcher() *Matcher {
+	return &Matcher{patterns: make(map[string]termPatterns)}
+}
+
+// Forget drops the patterns of the term with id
+func (m *Matcher) Forget(id string) {
+	delete(m.patterns, id)
+}
+
+func (m *Matcher) termPatterns(t Term) termPatterns {
+	p, ok := m.patterns[t.Id]
+	if !ok || !p.updatedAt.Equal(t.UpdatedAt) {
+		p = compileTerm(t)
+		m.patterns[t.Id] = p
+	}
+	return p
+}
+
+// Check returns the violations of content in lang: forbidden terms used and, for terms found in
+// the reference content, approved translations not used
+func (m *Matcher) Check(terms []Term, referenceLang string, referenceContent string, lang string, content string) []Violation {
+	result := make([]Violation, 0)
+	for _, t := range terms {
+		p := m.termPatterns(t)
+		if t.Forbidden {
+			word, pattern := t.Translations[lang], p.translations[lang]
+			if lang == t.Lang {
+				word, pattern = t.Term, p.term
+			}
+			if pattern != nil && word != "" && pattern.MatchString(content) {
+				result = append(result, Violation{TermId: t.Id, Term: t.Term, Forbidden: true, Found: word})
+			}
+			continue
+		}
+
+		if lang == t.Lang || t.Lang != referenceLang {
+			continue
+		}
+		expected, pattern := t.Translations[lang], p.translations[lang]
+		if pattern == nil || !p.term.MatchString(referenceContent) {
+			continue
+		}
+		if !pattern.MatchString(content) {
+			result = append(result, Violation{TermId: t.Id, Term: t.Term, Expected: expected})
+		}
+	}
+	return result
+}
+
+// wordPattern matches word in a text as whole word
+func wordPattern(word string, caseSensitive bool) *regexp.Regexp {
+	pattern := `(^|[^\pL\pN])` + regexp.QuoteMeta(word) + `($|[^\pL\pN])`
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.MustCompile(pattern)
+}
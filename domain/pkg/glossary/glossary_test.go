@@ -0,0 +1,141 @@
+package glossary
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	cart := Term{Id: "cart", Term: "cart", Lang: "en", Translations: Translations{"it": "carrello"}}
+	goTerm := Term{Id: "go", Term: "Go", Lang: "en", CaseSensitive: true, Translations: Translations{"it": "Go"}}
+	cat := Term{Id: "cat", Term: "cat", Lang: "en", Forbidden: true, Translations: Translations{"it": "gatto"}}
+	noTranslation := Term{Id: "checkout", Term: "checkout", Lang: "en"}
+
+	tests := []struct {
+		name             string
+		terms            []Term
+		referenceContent string
+		lang             string
+		content          string
+		want             []Violation
+	}{
+		{
+			name:             "approved translation used",
+			terms:            []Term{cart},
+			referenceContent: "Add to cart",
+			lang:             "it",
+			content:          "Aggiungi al carrello",
+			want:             []Violation{},
+		},
+		{
+			name:             "approved translation missing",
+			terms:            []Term{cart},
+			referenceContent: "Add to cart",
+			lang:             "it",
+			content:          "Aggiungi al cestino",
+			want:             []Violation{{TermId: "cart", Term: "cart", Expected: "carrello"}},
+		},
+		{
+			name:             "case insensitive",
+			terms:            []Term{cart},
+			referenceContent: "CART",
+			lang:             "it",
+			content:          "Carrello",
+			want:             []Violation{},
+		},
+		{
+			name:             "case sensitive term not in reference",
+			terms:            []Term{goTerm},
+			referenceContent: "go back",
+			lang:             "it",
+			content:          "torna indietro",
+			want:             []Violation{},
+		},
+		{
+			name:             "case sensitive translation with other case",
+			terms:            []Term{goTerm},
+			referenceContent: "Written in Go",
+			lang:             "it",
+			content:          "Scritto in GO",
+			want:             []Violation{{TermId: "go", Term: "Go", Expected: "Go"}},
+		},
+		{
+			name:             "term only inside a word",
+			terms:            []Term{cart},
+			referenceContent: "Cartography",
+			lang:             "it",
+			content:          "Cartografia",
+			want:             []Violation{},
+		},
+		{
+			name:             "term without translation in lang",
+			terms:            []Term{noTranslation},
+			referenceContent: "Go to checkout",
+			lang:             "it",
+			content:          "Vai alla cassa",
+			want:             []Violation{},
+		},
+		{
+			name:             "forbidden translation used",
+			terms:            []Term{cat},
+			referenceContent: "The cat",
+			lang:             "it",
+			content:          "Il gatto.",
+			want:             []Violation{{TermId: "cat", Term: "cat", Forbidden: true, Found: "gatto"}},
+		},
+		{
+			name:             "forbidden term used in its lang",
+			terms:            []Term{cat},
+			referenceContent: "",
+			lang:             "en",
+			content:          "A Cat!",
+			want:             []Violation{{TermId: "cat", Term: "cat", Forbidden: true, Found: "cat"}},
+		},
+		{
+			name:             "forbidden term only inside a word",
+			terms:            []Term{cat},
+			referenceContent: "",
+			lang:             "en",
+			content:          "concatenate",
+			want:             []Violation{},
+		},
+		{
+			name:             "many terms",
+			terms:            []Term{cart, cat},
+			referenceContent: "Cart of the cat",
+			lang:             "it",
+			content:          "Cestino del gatto",
+			want: []Violation{
+				{TermId: "cart", Term: "cart", Expected: "carrello"},
+				{TermId: "cat", Term: "cat", Forbidden: true, Found: "gatto"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMatcher().Check(tt.terms, "en", tt.referenceContent, tt.lang, tt.content)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherTermUpdated(t *testing.T) {
+	m := NewMatcher()
+	term := Term{Id: "cart", Term: "cart", Lang: "en", Translations: Translations{"it": "carrello"}, UpdatedAt: time.Now()}
+	got := m.Check([]Term{term}, "en", "Add to cart", "it", "Aggiungi al carrello")
+	if len(got) != 0 {
+		t.Fatalf("got %v, want no violations", got)
+	}
+
+	term.Translations = Translations{"it": "cestino"}
+	term.UpdatedAt = term.UpdatedAt.Add(time.Second)
+	got = m.Check([]Term{term}, "en", "Add to cart", "it", "Aggiungi al carrello")
+	want := []Violation{{TermId: "cart", Term: "cart", Expected: "cestino"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
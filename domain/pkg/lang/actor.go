@@ -0,0 +1,195 @@
+package lang
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/eventstore-go-v2/pkg/store"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+// LangActorState rebuilds the lang aggregates on event store notifies and persists them in
+// the registry projection
+type LangActorState struct {
+	store      *store.EventStore
+	repository *sqlx.DB
+}
+
+func newLangActorState() (*LangActorState, error) {
+	es, err := store.NewEventStore([]store.EventStoreConfigurator{store.WithPostgresqlRepository})
+	if err != nil {
+		return nil, err
+	}
+	repo, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &LangActorState{
+		store:      &es,
+		repository: repo,
+	}, nil
+}
+
+var LangActorAddress = actor.NewAddress("locale", "lang-actor")
+
+func NewLangActor() (*actor.Actor, error) {
+	state, err := newLangActorState()
+	if err != nil {
+		return nil, err
+	}
+	a, err := actor.NewActor(LangActorAddress, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// subscribe event store notifies
+	addSubMsg := actor.NewAddSubcriptionMessage(a.GetAddress(), store.EventStoreAddress)
+	err = actor.SendMessage(addSubMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+var insertUpdateLang string = `--insert sql
+INSERT INTO
+locale.lang (
+	code,
+	display_name,
+	direction,
+	plural_categories,
+	enabled,
+	version,
+	updated_at,
+	updated_by
+)
+VALUES (
+	:code,
+	:display_name,
+	:direction,
+	:plural_categories,
+	:enabled,
+	:version,
+	:updated_at,
+	:updated_by
+)
+ON CONFLICT (code)
+DO UPDATE SET
+    display_name = :display_name,
+    direction = :direction,
+    plural_categories = :plural_categories,
+    enabled = :enabled,
+    version = :version,
+    updated_at = :updated_at,
+    updated_by = :updated_by;
+`
+
+// updateLang rebuilds the lang aggregate with aggregateID and persists it;
+// events of other aggregates are ignored
+func (state *LangActorState) updateLang(aggregateID string) error {
+	if !strings.HasPrefix(aggregateID, LangAggregateName+"-") {
+		return nil
+	}
+
+	evts, _, err := state.store.Repository.RetriveByAggregateID(aggregateID)
+	if err != nil {
+		return err
+	}
+	if len(evts) == 0 || evts[0].AggregateName != LangAggregateName {
+		return nil
+	}
+
+	l := Lang{}
+	err = l.Reduce(evts)
+	if err != nil {
+		return err
+	}
+
+	_, err = state.repository.NamedExec(insertUpdateLang, l)
+	return err
+}
+
+func (state *LangActorState) getLangs(enabledOnly bool) ([]Lang, error) {
+	result := make([]Lang, 0)
+	query := "SELECT * FROM locale.lang"
+	if enabledOnly {
+		query += " WHERE enabled = true"
+	}
+	query += " ORDER BY code;"
+
+	err := state.repository.Select(&result, query)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (state *LangActorState) getLang(code string) (Lang, error) {
+	result := Lang{}
+	err := state.repository.Get(&result, "SELECT * FROM locale.lang WHERE code = $1;", code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrLangNotFound
+	}
+	return result, err
+}
+
+// RetriveLangsMessageBody is the query message for the registered langs
+type RetriveLangsMessageBody struct {
+	EnabledOnly bool
+}
+
+type RetriveLangsMessageBodyResult struct {
+	Langs []Lang
+}
+
+// RetriveLangMessageBody is the query message for the lang with canonical code;
+// ErrLangNotFound if not registered
+type RetriveLangMessageBody struct {
+	Code string
+}
+
+type RetriveLangMessageBodyResult struct {
+	Lang Lang
+}
+
+func (state *LangActorState) Process(msg actor.Message) {
+	switch payload := msg.Body.(type) {
+	case store.StoreEventAddedBody:
+		err := state.updateLang(payload.AggregateID)
+		if err != nil {
+			slog.Error("error on update lang registry", slog.String("aggregateId", payload.AggregateID), slog.String("err", err.Error()))
+		}
+
+	case RetriveLangsMessageBody:
+		langs, err := state.getLangs(payload.EnabledOnly)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(RetriveLangsMessageBodyResult{langs}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case RetriveLangMessageBody:
+		l, err := state.getLang(payload.Code)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(RetriveLangMessageBodyResult{l}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	}
+}
+
+func (state *LangActorState) GetState() any {
+	return nil
+}
+
+func (state *LangActorState) Shutdown() {
+	err := state.repository.Close()
+	if err != nil {
+		slog.Error("error closing database connection", slog.String("err", err.Error()))
+	}
+	state.repository = nil
+	state.store = nil
+}
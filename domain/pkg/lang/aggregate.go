@@ -0,0 +1,60 @@
+package lang
+
+import (
+	"fmt"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/eventstore-go-v2/pkg/utils"
+)
+
+// Reduce applies events in order and stops on the first one that can not be decoded
+func (l *Lang) Reduce(evts []events.StoreEvent) error {
+	for _, evt := range evts {
+		if err := l.Apply(evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Lang) Apply(evt events.StoreEvent) error {
+	// version is the number of events in aggregate stream
+	l.Version++
+	l.UpdatedAt = evt.CreatedAt
+	l.UpdatedBy = evt.CreatedBy
+
+	switch evt.EventType {
+	case RegisterLangStoreEventType:
+		payload, err := decodePayload[RegisterLangPayload](evt)
+		if err != nil {
+			return err
+		}
+		l.Code = payload.Code
+		l.DisplayName = payload.DisplayName
+		l.Direction = payload.Direction
+		l.PluralCategories = payload.PluralCategories
+		l.Enabled = true
+	case ChangeLangStoreEventType:
+		payload, err := decodePayload[ChangeLangPayload](evt)
+		if err != nil {
+			return err
+		}
+		l.DisplayName = payload.DisplayName
+		l.Direction = payload.Direction
+		l.PluralCategories = payload.PluralCategories
+	case EnableLangStoreEventType:
+		l.Enabled = true
+	case DisableLangStoreEventType:
+		l.Enabled = false
+	}
+	return nil
+}
+
+func decodePayload[T any](evt events.StoreEvent) (T, error) {
+	var result T
+	payload, err := utils.DecodePayload[T](evt.PayloadData)
+	if err != nil {
+		return result, fmt.Errorf("error on decode payload %s: %w", evt.EventType, err)
+	}
+	return *payload, nil
+}
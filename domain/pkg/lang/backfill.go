@@ -0,0 +1,64 @@
+package lang
+
+import (
+	"log/slog"
+
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/eventstore-go-v2/pkg/store"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+// BackfillLangs registers the langs of locale items created before the registry, with code as display
+// name and the defaults of code; langs already registered are skipped, so it can run at every startup.
+// Register events go through the event store actor so the registry projection is updated
+func BackfillLangs(userID string) error {
+	repo, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	codes := make([]string, 0)
+	err = repo.Select(&codes, "SELECT DISTINCT lang FROM locale.localeitems_list;")
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		evt, err := NewRegisterEvent(code, code, "", nil, userID)
+		if err != nil {
+			slog.Warn("lang of locale items can not be registered", slog.String("lang", code), slog.String("err", err.Error()))
+			continue
+		}
+
+		checkMsg := actor.NewMessage(
+			store.EventStoreAddress,
+			nil,
+			store.CheckExistenceByAggregateIDBody{Id: evt.AggregateID},
+			true,
+		)
+		check, err := actor.SendMessageWithResponse[store.CheckExistenceByAggregateIDBodyResult](checkMsg)
+		if err != nil {
+			return err
+		}
+		if check.Exists {
+			continue
+		}
+
+		addMsg := actor.NewMessage(
+			store.EventStoreAddress,
+			nil,
+			store.AddEventBody{Event: evt},
+			true,
+		)
+		result, err := actor.SendMessageWithResponse[store.AddEventBodyResult](addMsg)
+		if err != nil {
+			return err
+		}
+		if !result.Success {
+			return ErrBackfillLang
+		}
+		slog.Info("lang of locale items registered", slog.String("aggregateId", evt.AggregateID))
+	}
+	return nil
+}
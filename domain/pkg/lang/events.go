@@ -0,0 +1,118 @@
+package lang
+
+import (
+	"strings"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+)
+
+const LangAggregateName = "lang"
+
+// langSchemaVersion is the payload schema version of lang events
+const langSchemaVersion = 1
+
+// AggregateID returns the aggregate id of the lang with canonical code
+func AggregateID(code string) string {
+	return LangAggregateName + "-" + code
+}
+
+const RegisterLangStoreEventType = "lang-registered"
+
+type RegisterLangPayload struct {
+	SchemaVersion    int
+	Code             string
+	DisplayName      string
+	Direction        string
+	PluralCategories PluralCategories
+}
+
+// NewRegisterEvent registers the lang with code in its canonical form, enabled; empty direction and
+// plural categories are the defaults of code
+func NewRegisterEvent(code string, displayName string, direction string, pluralCategories []string, userID string) (events.StoreEvent, error) {
+	code, err := Canonicalize(code)
+	if err != nil {
+		return events.StoreEvent{}, err
+	}
+	displayName, direction, categories, err := verifyProperties(code, displayName, direction, pluralCategories)
+	if err != nil {
+		return events.StoreEvent{}, err
+	}
+
+	payload := RegisterLangPayload{
+		SchemaVersion:    langSchemaVersion,
+		Code:             code,
+		DisplayName:      displayName,
+		Direction:        direction,
+		PluralCategories: categories,
+	}
+
+	aggregateID := AggregateID(code)
+	return events.NewStoreEvent(RegisterLangStoreEventType, LangAggregateName, userID, payload, &aggregateID)
+}
+
+const ChangeLangStoreEventType = "lang-changed"
+
+type ChangeLangPayload struct {
+	SchemaVersion    int
+	DisplayName      string
+	Direction        string
+	PluralCategories PluralCategories
+}
+
+// NewChangeEvent replaces display name, direction and plural categories of the lang with canonical code
+func NewChangeEvent(code string, displayName string, direction string, pluralCategories []string, userID string) (events.StoreEvent, error) {
+	displayName, direction, categories, err := verifyProperties(code, displayName, direction, pluralCategories)
+	if err != nil {
+		return events.StoreEvent{}, err
+	}
+
+	payload := ChangeLangPayload{
+		SchemaVersion:    langSchemaVersion,
+		DisplayName:      displayName,
+		Direction:        direction,
+		PluralCategories: categories,
+	}
+
+	aggregateID := AggregateID(code)
+	return events.NewStoreEvent(ChangeLangStoreEventType, LangAggregateName, userID, payload, &aggregateID)
+}
+
+const EnableLangStoreEventType = "lang-enabled"
+
+const DisableLangStoreEventType = "lang-disabled"
+
+type EnableLangPayload struct {
+	SchemaVersion int
+}
+
+func NewEnableEvent(code string, userID string) (events.StoreEvent, error) {
+	aggregateID := AggregateID(code)
+	return events.NewStoreEvent(EnableLangStoreEventType, LangAggregateName, userID, EnableLangPayload{langSchemaVersion}, &aggregateID)
+}
+
+func NewDisableEvent(code string, userID string) (events.StoreEvent, error) {
+	aggregateID := AggregateID(code)
+	return events.NewStoreEvent(DisableLangStoreEventType, LangAggregateName, userID, EnableLangPayload{langSchemaVersion}, &aggregateID)
+}
+
+// verifyProperties checks the lang properties and fills the defaults of code
+func verifyProperties(code string, displayName string, direction string, pluralCategories []string) (string, string, PluralCategories, error) {
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return "", "", nil, ErrDisplayNameRequired
+	}
+
+	switch direction {
+	case "":
+		direction = DefaultDirection(code)
+	case LeftToRight, RightToLeft:
+	default:
+		return "", "", nil, ErrInvalidDirection
+	}
+
+	categories, err := NormalizePluralCategories(code, pluralCategories)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return displayName, direction, categories, nil
+}
@@ -0,0 +1,194 @@
+// Package lang is the registry of the languages enabled for translations: every lang is an event
+// sourced aggregate identified by its BCP 47 canonical code
+package lang
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pix303/localemgmt-go/domain/pkg/plural"
+)
+
+var (
+	ErrInvalidLangCode     = errors.New("invalid BCP 47 lang code")
+	ErrInvalidDirection    = errors.New("text direction must be ltr or rtl")
+	ErrInvalidPluralRules  = errors.New("plural categories must be CLDR categories including other")
+	ErrDisplayNameRequired = errors.New("display name is required")
+	ErrLangNotFound        = errors.New("lang not found")
+	ErrBackfillLang        = errors.New("error on store lang registered event")
+)
+
+// text directions
+const (
+	LeftToRight = "ltr"
+	RightToLeft = "rtl"
+)
+
+// deprecatedLanguages maps deprecated ISO 639 language subtags to their preferred value
+var deprecatedLanguages = map[string]string{
+	"iw": "he",
+	"in": "id",
+	"ji": "yi",
+	"jw": "jv",
+	"mo": "ro",
+}
+
+// Canonicalize returns the BCP 47 canonical form of code: '_' separators become '-', language and
+// variants are lowercase, script is titlecase and region uppercase, as en-US from en_us; extensions
+// and private use subtags are not allowed
+func Canonicalize(code string) (string, error) {
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"), "-")
+
+	language := strings.ToLower(subtags[0])
+	if !isAlpha(language) || len(language) < 2 || len(language) > 3 {
+		return "", fmt.Errorf("%w: %s", ErrInvalidLangCode, code)
+	}
+	if preferred, ok := deprecatedLanguages[language]; ok {
+		language = preferred
+	}
+	result := []string{language}
+	subtags = subtags[1:]
+
+	// script
+	if len(subtags) > 0 && len(subtags[0]) == 4 && isAlpha(subtags[0]) {
+		script := strings.ToLower(subtags[0])
+		result = append(result, strings.ToUpper(script[:1])+script[1:])
+		subtags = subtags[1:]
+	}
+
+	// region
+	if len(subtags) > 0 && (len(subtags[0]) == 2 && isAlpha(subtags[0]) || len(subtags[0]) == 3 && isDigit(subtags[0])) {
+		result = append(result, strings.ToUpper(subtags[0]))
+		subtags = subtags[1:]
+	}
+
+	// variants
+	variants := make([]string, 0, len(subtags))
+	for _, subtag := range subtags {
+		variant := strings.ToLower(subtag)
+		if !isVariant(variant) || slices.Contains(variants, variant) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidLangCode, code)
+		}
+		variants = append(variants, variant)
+	}
+
+	return strings.Join(append(result, variants...), "-"), nil
+}
+
+func isVariant(subtag string) bool {
+	if !isAlphanumeric(subtag) {
+		return false
+	}
+	if len(subtag) >= 5 && len(subtag) <= 8 {
+		return true
+	}
+	return len(subtag) == 4 && isDigit(subtag[:1])
+}
+
+func isAlpha(s string) bool {
+	return s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+}
+
+func isDigit(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+func isAlphanumeric(s string) bool {
+	return s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") == ""
+}
+
+// rightToLeftLanguages are the languages written right to left in their default script
+var rightToLeftLanguages = []string{"ar", "he", "fa", "ur", "yi", "ps", "dv", "sd", "ug", "ckb"}
+
+// rightToLeftScripts are the scripts written right to left
+var rightToLeftScripts = []string{"Arab", "Hebr", "Thaa", "Syrc", "Nkoo", "Adlm", "Rohg"}
+
+// DefaultDirection returns the text direction of a canonical code from its script or,
+// if not given, from its language
+func DefaultDirection(code string) string {
+	subtags := strings.Split(code, "-")
+	if len(subtags) > 1 && len(subtags[1]) == 4 && isAlpha(subtags[1]) {
+		if slices.Contains(rightToLeftScripts, subtags[1]) {
+			return RightToLeft
+		}
+		return LeftToRight
+	}
+	if slices.Contains(rightToLeftLanguages, subtags[0]) {
+		return RightToLeft
+	}
+	return LeftToRight
+}
+
+// PluralCategories are the CLDR plural categories used by a lang
+type PluralCategories []string
+
+// NormalizePluralCategories checks categories and returns them without duplicates in CLDR order;
+// empty categories are the default ones of code
+func NormalizePluralCategories(code string, categories []string) (PluralCategories, error) {
+	if len(categories) == 0 {
+		return slices.Clone(plural.RequiredCategories(code)), nil
+	}
+
+	for _, category := range categories {
+		if !slices.Contains(plural.Categories, category) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPluralRules, category)
+		}
+	}
+	if !slices.Contains(categories, plural.Other) {
+		return nil, ErrInvalidPluralRules
+	}
+
+	result := make(PluralCategories, 0, len(categories))
+	for _, category := range plural.Categories {
+		if slices.Contains(categories, category) {
+			result = append(result, category)
+		}
+	}
+	return result, nil
+}
+
+// Value implements driver.Valuer to persist categories as json
+func (categories PluralCategories) Value() (driver.Value, error) {
+	if categories == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(categories))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner to read categories from json
+func (categories *PluralCategories) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*categories = PluralCategories{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type %T for plural categories", src)
+	}
+	return json.Unmarshal(data, categories)
+}
+
+// Lang is the aggregate of a registered language
+type Lang struct {
+	Code             string           `db:"code" json:"code"`
+	DisplayName      string           `db:"display_name" json:"displayName"`
+	Direction        string           `db:"direction" json:"direction"`
+	PluralCategories PluralCategories `db:"plural_categories" json:"pluralCategories"`
+	Enabled          bool             `db:"enabled" json:"enabled"`
+	Version          int              `db:"version" json:"version"`
+	UpdatedAt        time.Time        `db:"updated_at" json:"updatedAt"`
+	UpdatedBy        string           `db:"updated_by" json:"updatedBy"`
+}
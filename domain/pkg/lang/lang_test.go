@@ -0,0 +1,128 @@
+package lang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"en", "en"},
+		{" EN ", "en"},
+		{"en_us", "en-US"},
+		{"EN-us", "en-US"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+		{"ZH_HANS", "zh-Hans"},
+		{"sr-latn", "sr-Latn"},
+		{"es-419", "es-419"},
+		{"ckb-iq", "ckb-IQ"},
+		{"und", "und"},
+		{"iw", "he"},
+		{"in-ID", "id-ID"},
+		{"mo", "ro"},
+		{"sl-rozaj", "sl-rozaj"},
+		{"sl-IT-Rozaj-Biske", "sl-IT-rozaj-biske"},
+		{"de-CH-1996", "de-CH-1996"},
+		{"ca-es-VALENCIA", "ca-ES-valencia"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := Canonicalize(tt.code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"empty", ""},
+		{"blank", "  "},
+		{"one letter language", "e"},
+		{"long language", "english"},
+		{"digit language", "e1"},
+		{"empty subtag", "en--US"},
+		{"trailing separator", "en-"},
+		{"region twice", "en-US-GB"},
+		{"short variant", "de-1"},
+		{"four letters variant", "de-CH-abcd"},
+		{"long variant", "de-abcdefghi"},
+		{"duplicate variant", "sl-rozaj-ROZAJ"},
+		{"extension", "en-u-ca-gregory"},
+		{"private use", "en-x-private"},
+		{"private use only", "x-private"},
+		{"non ascii", "fr-ÇA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize(tt.code)
+			if !errors.Is(err, ErrInvalidLangCode) {
+				t.Errorf("got %q, %v, want %v", got, err, ErrInvalidLangCode)
+			}
+		})
+	}
+}
+
+func TestDefaultDirection(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"en", LeftToRight},
+		{"ar", RightToLeft},
+		{"ar-EG", RightToLeft},
+		{"he", RightToLeft},
+		{"az-Arab", RightToLeft},
+		{"pa-Arab-PK", RightToLeft},
+		{"uz-Latn", LeftToRight},
+		{"ar-Latn", LeftToRight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := DefaultDirection(tt.code); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePluralCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		want       PluralCategories
+		err        error
+	}{
+		{"cldr order", []string{"other", "one", "few"}, PluralCategories{"one", "few", "other"}, nil},
+		{"duplicates", []string{"other", "one", "other"}, PluralCategories{"one", "other"}, nil},
+		{"other only", []string{"other"}, PluralCategories{"other"}, nil},
+		{"missing other", []string{"one"}, nil, ErrInvalidPluralRules},
+		{"unknown category", []string{"one", "several", "other"}, nil, ErrInvalidPluralRules},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePluralCategories("en", tt.categories)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
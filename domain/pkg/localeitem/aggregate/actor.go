@@ -1,21 +1,31 @@
 package aggregate
 
 import (
+	"errors"
 	"github.com/pix303/cinecity/pkg/actor"
 	"github.com/pix303/cinecity/pkg/batch"
 	"log/slog"
+	"time"
 
 	"github.com/pix303/eventstore-go-v2/pkg/store"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
 )
 
 var (
 	ErrToRetriveAggregateEvents = "error on retriving aggregate events"
 	ErrToPersistAggregate       = "error on persisting aggregate"
+	ErrToManageSnapshot         = "error on managing aggregate snapshot"
+	ErrToReduceAggregateEvents  = "error on reducing aggregate events"
+	ErrToAppendAggregateEvents  = "error on appending aggregate events"
 )
 
+// errNotLocaleItemAggregate is returned rebuilding the events of other aggregates, like langs, sharing the event store
+var errNotLocaleItemAggregate = errors.New("not a locale item aggregate")
+
 // LocaleItemAggregateState is the actor state for the aggregate persistence
 type LocaleItemAggregateState struct {
-	store     *store.EventStore
+	events    *LocaleItemEventRepository
+	snapshots *LocaleItemAggregateSnapshotRepository
 	batcher   *batch.Batcher
 	aggregate *LocaleItemAggregate
 }
@@ -25,21 +35,31 @@ var LocaleItemAggregateAddress = actor.NewAddress("local", "aggregate")
 func NewLocaleItemAggregateActor() (*actor.Actor, error) {
 
 	// create event store reference
-	es, err := store.NewEventStore([]store.EventStoreConfigurator{store.WithPostgresqlRepository})
+	evts, err := NewLocaleItemEventRepository()
+	if err != nil {
+		return nil, err
+	}
 
+	// create snapshot repository and drop snapshots of old aggregate shapes
+	snapshots, err := NewLocaleItemAggregateSnapshotRepository()
 	if err != nil {
 		return nil, err
 	}
+	err = snapshots.InvalidateOutdated()
+	if err != nil {
+		slog.Warn(ErrToManageSnapshot, slog.String("error", err.Error()))
+	}
 
 	// create actor state
 	aggregate := NewLocaleItemAggregate()
 	s := LocaleItemAggregateState{
-		store:     &es,
+		events:    evts,
+		snapshots: snapshots,
 		aggregate: &aggregate,
 	}
 
 	a, err := actor.NewActor(
-		actor.NewAddress("local", "localeitem-aggregate"),
+		LocaleItemAggregateAddress,
 		&s,
 	)
 
@@ -90,14 +110,170 @@ func NewLocaleItemAggregateActor() (*actor.Actor, error) {
 		return nil, err
 	}
 
+	commentsPersistState, err := NewLocaleItemAggregateCommentsState()
+	if err != nil {
+		return nil, err
+	}
+	commentsPersistActor, err := actor.NewActor(
+		LocaleItemAggregateCommentsAddress,
+		commentsPersistState,
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = actor.RegisterActor(&commentsPersistActor)
+	if err != nil {
+		return nil, err
+	}
+
+	writerState, err := NewLocaleItemWriterState()
+	if err != nil {
+		return nil, err
+	}
+	writerActor, err := actor.NewActor(
+		LocaleItemWriterAddress,
+		writerState,
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = actor.RegisterActor(&writerActor)
+	if err != nil {
+		return nil, err
+	}
+
 	return &a, nil
 }
 
+// LocaleItemEventsAppendedBody notifies the events of the aggregates appended in batch by the
+// locale item writer, which the event store does not notify; projections are updated once for all
+type LocaleItemEventsAppendedBody struct {
+	AggregateIDs []string
+}
+
+type GetLocaleItemAggregateVersionBody struct {
+	Id string
+}
+
+type GetLocaleItemAggregateVersionBodyResult struct {
+	Version int
+}
+
+// GetLocaleItemAggregateAtBody is the query message to rebuild the aggregate as it was at a version
+// or at a time; zero values are ignored
+type GetLocaleItemAggregateAtBody struct {
+	Id      string
+	Version int
+	At      time.Time
+}
+
+type GetLocaleItemAggregateAtBodyResult struct {
+	Aggregate LocaleItemAggregate
+}
+
+// GetLocaleItemHistoryBody is the query message for the translation change history, newest first;
+// Lang is optional
+type GetLocaleItemHistoryBody struct {
+	Id       string
+	Lang     string
+	Page     int
+	PageSize int
+}
+
+type GetLocaleItemHistoryBodyResult struct {
+	Entries []HistoryEntry
+	Total   int
+}
+
 func (state *LocaleItemAggregateState) Process(msg actor.Message) {
-	switch msg.Body.(type) {
+	switch payload := msg.Body.(type) {
 	case store.StoreEventAddedBody:
 		state.batcher.Add(msg)
+	case LocaleItemEventsAppendedBody:
+		state.updateAggregateStates(payload.AggregateIDs)
+	case GetLocaleItemAggregateVersionBody:
+		version, err := state.getVersion(payload.Id)
+		if err != nil {
+			slog.Warn(ErrToRetriveAggregateEvents, slog.String("error", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(GetLocaleItemAggregateVersionBodyResult{Version: version}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	case GetLocaleItemAggregateAtBody:
+		result, err := state.getAggregateAt(payload)
+		if err != nil {
+			slog.Warn(ErrToRetriveAggregateEvents, slog.String("error", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(GetLocaleItemAggregateAtBodyResult{Aggregate: result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	case GetLocaleItemHistoryBody:
+		result, err := state.getHistory(payload)
+		if err != nil {
+			slog.Warn(ErrToRetriveAggregateEvents, slog.String("error", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(result, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	}
+}
+
+// getHistory returns a page of the aggregate history, newest entries first
+func (state *LocaleItemAggregateState) getHistory(params GetLocaleItemHistoryBody) (GetLocaleItemHistoryBodyResult, error) {
+	result := GetLocaleItemHistoryBodyResult{Entries: make([]HistoryEntry, 0)}
+	evts, err := state.events.RetriveByEventTypes(params.Id, HistoryEventTypes)
+	if err != nil {
+		return result, err
+	}
+
+	history, err := NewHistory(evts)
+	if err != nil {
+		return result, err
+	}
+	filtered := make([]HistoryEntry, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		if params.Lang == "" || history[i].Lang == params.Lang {
+			filtered = append(filtered, history[i])
+		}
 	}
+
+	result.Total = len(filtered)
+	start := min((params.Page-1)*params.PageSize, len(filtered))
+	end := min(start+params.PageSize, len(filtered))
+	result.Entries = filtered[start:end]
+	return result, nil
+}
+
+// getAggregateAt rebuilds the aggregate up to the requested version or time, the earliest of them;
+// an empty aggregate if it did not exist yet
+func (state *LocaleItemAggregateState) getAggregateAt(params GetLocaleItemAggregateAtBody) (LocaleItemAggregate, error) {
+	version := params.Version
+	if !params.At.IsZero() {
+		atVersion, err := state.events.VersionAt(params.Id, params.At)
+		if err != nil {
+			return NewLocaleItemAggregate(), err
+		}
+		if atVersion == 0 {
+			return NewLocaleItemAggregate(), nil
+		}
+		if version == 0 || atVersion < version {
+			version = atVersion
+		}
+	}
+
+	result, _, err := state.reduceAggregate(params.Id, version)
+	if errors.Is(err, errNotLocaleItemAggregate) {
+		return NewLocaleItemAggregate(), nil
+	}
+	return result, err
+}
+
+// getVersion returns the current version of aggregate as the number of its stored events
+func (state *LocaleItemAggregateState) getVersion(aggregateID string) (int, error) {
+	return state.events.Version(aggregateID)
 }
 
 func (state *LocaleItemAggregateState) updateAggregateState(msg actor.Message) {
@@ -107,32 +283,170 @@ func (state *LocaleItemAggregateState) updateAggregateState(msg actor.Message) {
 		return
 	}
 
-	// TODO: use actor??
-	evts, _, err := state.store.Repository.RetriveByAggregateID(body.AggregateID)
+	newAgg, err := state.rebuildAggregate(body.AggregateID)
+	if err != nil {
+		return
+	}
+	state.aggregate = &newAgg
+
+	state.sendToProjections(
+		AddLocaleItemAggregateDetailBody{newAgg},
+		AddLocaleItemAggregateListBody{newAgg},
+		AddLocaleItemAggregateCommentsBody{newAgg},
+	)
+}
+
+// updateAggregateStates rebuilds the aggregates reading the events after their snapshots all at once
+// and sends them to projections in batch
+func (state *LocaleItemAggregateState) updateAggregateStates(aggregateIDs []string) {
+	evts, err := state.events.RetriveAfterSnapshots(aggregateIDs)
 	if err != nil {
 		slog.Warn(ErrToRetriveAggregateEvents, slog.String("error", err.Error()))
 		return
 	}
+	evtsByAggregate := make(map[string][]VersionedEvent)
+	for _, evt := range evts {
+		evtsByAggregate[evt.AggregateID] = append(evtsByAggregate[evt.AggregateID], evt)
+	}
 
+	aggregates := make([]LocaleItemAggregate, 0, len(aggregateIDs))
+	for _, aggregateID := range aggregateIDs {
+		newAgg, err := state.reduceAfterSnapshot(aggregateID, evtsByAggregate[aggregateID])
+		if err != nil {
+			continue
+		}
+		aggregates = append(aggregates, newAgg)
+	}
+
+	state.sendToProjections(
+		AddLocaleItemAggregateDetailBatchBody{aggregates},
+		AddLocaleItemAggregateListBatchBody{aggregates},
+		AddLocaleItemAggregateCommentsBatchBody{aggregates},
+	)
+}
+
+// reduceAfterSnapshot applies to the latest snapshot of aggregate the events following it, to a new
+// aggregate if they are all its events; if they do not follow the snapshot, because it changed
+// meanwhile, the aggregate is rebuilt on its own
+func (state *LocaleItemAggregateState) reduceAfterSnapshot(aggregateID string, evts []VersionedEvent) (LocaleItemAggregate, error) {
 	newAgg := NewLocaleItemAggregate()
-	state.aggregate = &newAgg
-	state.aggregate.Reduce(evts)
+	if len(evts) > 0 && evts[0].Version > 1 {
+		snapshot, found, err := state.snapshots.Load(aggregateID)
+		if err != nil {
+			slog.Warn(ErrToManageSnapshot, slog.String("error", err.Error()))
+		}
+		if found {
+			newAgg = snapshot
+		}
+	}
+	snapshotVersion := newAgg.Version
+	if len(evts) == 0 || evts[0].Version != snapshotVersion+1 {
+		return state.rebuildAggregate(aggregateID)
+	}
+
+	for _, evt := range evts {
+		err := newAgg.Apply(evt.StoreEvent)
+		if err != nil {
+			slog.Error(ErrToReduceAggregateEvents, slog.String("aggregateId", aggregateID), slog.String("error", err.Error()))
+			return LocaleItemAggregate{}, err
+		}
+	}
+
+	if newAgg.Version-snapshotVersion >= SnapshotEvery {
+		err := state.snapshots.Save(newAgg)
+		if err != nil {
+			slog.Warn(ErrToManageSnapshot, slog.String("error", err.Error()))
+		}
+	}
+	return newAgg, nil
+}
 
+// rebuildAggregate reduces the aggregate events starting from its latest snapshot and
+// saves a new snapshot every SnapshotEvery events
+func (state *LocaleItemAggregateState) rebuildAggregate(aggregateID string) (LocaleItemAggregate, error) {
+	newAgg, snapshotVersion, err := state.reduceAggregate(aggregateID, 0)
+	if err != nil {
+		return LocaleItemAggregate{}, err
+	}
+
+	if newAgg.Version-snapshotVersion >= SnapshotEvery {
+		err = state.snapshots.Save(newAgg)
+		if err != nil {
+			slog.Warn(ErrToManageSnapshot, slog.String("error", err.Error()))
+		}
+	}
+
+	return newAgg, nil
+}
+
+// reduceAggregate reduces the aggregate events up to version, all of them with 0, reading only the
+// ones after its latest snapshot if not newer than version; it returns the snapshot version used
+func (state *LocaleItemAggregateState) reduceAggregate(aggregateID string, version int) (LocaleItemAggregate, int, error) {
+	current, err := state.events.Version(aggregateID)
+	if err != nil {
+		slog.Warn(ErrToRetriveAggregateEvents, slog.String("error", err.Error()))
+		return LocaleItemAggregate{}, 0, err
+	}
+	if current == 0 {
+		return LocaleItemAggregate{}, 0, errNotLocaleItemAggregate
+	}
+	if version == 0 || version > current {
+		version = current
+	}
+
+	// a snapshot newer than version, or than all events if the store was rebuilt, is not usable
+	newAgg, found, err := state.snapshots.Load(aggregateID)
+	if err != nil {
+		slog.Warn(ErrToManageSnapshot, slog.String("error", err.Error()))
+	}
+	if !found || newAgg.Version > version {
+		newAgg = NewLocaleItemAggregate()
+	}
+	snapshotVersion := newAgg.Version
+
+	evts, err := state.events.RetriveAfterVersion(aggregateID, snapshotVersion, version)
+	if err != nil {
+		slog.Warn(ErrToRetriveAggregateEvents, slog.String("error", err.Error()))
+		return LocaleItemAggregate{}, 0, err
+	}
+	// events of other aggregates sharing the event store, like langs, have no snapshot
+	if snapshotVersion == 0 && (len(evts) == 0 || evts[0].AggregateName != domain.LocaleItemAggregateName) {
+		return LocaleItemAggregate{}, 0, errNotLocaleItemAggregate
+	}
+
+	err = newAgg.Reduce(evts)
+	if err != nil {
+		// projections keep their last good state until events can be read again
+		slog.Error(ErrToReduceAggregateEvents, slog.String("aggregateId", aggregateID), slog.String("error", err.Error()))
+		return LocaleItemAggregate{}, 0, err
+	}
+	return newAgg, snapshotVersion, nil
+}
+
+// sendToProjections sends the given bodies to detail, list and comments projections
+func (state *LocaleItemAggregateState) sendToProjections(detailBody any, listBody any, commentsBody any) {
 	detailMsg := actor.NewMessage(
 		LocaleItemAggregateDetailAddress,
 		LocaleItemAggregateAddress,
-		AddLocaleItemAggregateDetailBody{*state.aggregate},
+		detailBody,
 		false,
 	)
 
 	listMsg := actor.NewMessage(
 		LocaleItemAggregateListAddress,
 		LocaleItemAggregateAddress,
-		AddLocaleItemAggregateListBody{*state.aggregate},
+		listBody,
 		false,
 	)
 
-	err = actor.SendMessage(detailMsg)
+	commentsMsg := actor.NewMessage(
+		LocaleItemAggregateCommentsAddress,
+		LocaleItemAggregateAddress,
+		commentsBody,
+		false,
+	)
+
+	err := actor.SendMessage(detailMsg)
 	if err != nil {
 		slog.Error(ErrToPersistAggregate, slog.String("error", err.Error()))
 	}
@@ -141,6 +455,11 @@ func (state *LocaleItemAggregateState) updateAggregateState(msg actor.Message) {
 	if err != nil {
 		slog.Error(ErrToPersistAggregate, slog.String("error", err.Error()))
 	}
+
+	err = actor.SendMessage(commentsMsg)
+	if err != nil {
+		slog.Error(ErrToPersistAggregate, slog.String("error", err.Error()))
+	}
 }
 
 func (state *LocaleItemAggregateState) GetState() any {
@@ -148,7 +467,16 @@ func (state *LocaleItemAggregateState) GetState() any {
 }
 
 func (state *LocaleItemAggregateState) Shutdown() {
-	state.store = nil
+	err := state.snapshots.Close()
+	if err != nil {
+		slog.Error("fail to close snapshot repository", slog.String("error", err.Error()))
+	}
+	state.snapshots = nil
+	err = state.events.Close()
+	if err != nil {
+		slog.Error("fail to close events repository", slog.String("error", err.Error()))
+	}
+	state.events = nil
 	state.batcher = nil
 	state.aggregate = nil
 }
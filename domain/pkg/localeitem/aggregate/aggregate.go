@@ -3,11 +3,13 @@ package aggregate
 import (
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/pix303/eventstore-go-v2/pkg/events"
-	"github.com/pix303/eventstore-go-v2/pkg/utils"
 	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+	"github.com/pix303/localemgmt-go/domain/pkg/plural"
 )
 
 const DEFAULT_CONTEXT = "default"
@@ -15,6 +17,9 @@ const DEFAULT_CONTEXT = "default"
 type TranslationItem struct {
 	Lang      string
 	Content   string
+	Plurals   plural.Forms
+	Status    string
+	IsStale   bool
 	CreatedBy string
 	CreatedAt time.Time
 	UpdatedBy string
@@ -25,6 +30,7 @@ func NewTranslationItem(lang, content, userId string) TranslationItem {
 	return TranslationItem{
 		Lang:      lang,
 		Content:   content,
+		Status:    domain.DraftTranslationStatus,
 		CreatedBy: userId,
 		UpdatedBy: userId,
 		CreatedAt: time.Now().UTC(),
@@ -35,15 +41,47 @@ func NewTranslationItem(lang, content, userId string) TranslationItem {
 func (item *TranslationItem) UpdateTranslationItem(lang, content, userId string) {
 	item.Lang = lang
 	item.Content = content
+	item.Status = domain.DraftTranslationStatus
 	item.UpdatedBy = userId
 	item.UpdatedAt = time.Now().UTC()
 }
 
+// translationStatusTransitions maps every target status to the statuses it can be reached from
+var translationStatusTransitions = map[string][]string{
+	domain.NeedsReviewTranslationStatus: {domain.DraftTranslationStatus, domain.RejectedTranslationStatus},
+	domain.ApprovedTranslationStatus:    {domain.NeedsReviewTranslationStatus},
+	domain.RejectedTranslationStatus:    {domain.NeedsReviewTranslationStatus},
+}
+
+// CanChangeStatus reports if the workflow allows to move translation to status
+func (item *TranslationItem) CanChangeStatus(status string) bool {
+	current := item.Status
+	if current == "" {
+		current = domain.DraftTranslationStatus
+	}
+	for _, from := range translationStatusTransitions[status] {
+		if from == current {
+			return true
+		}
+	}
+	return false
+}
+
 type LocaleItemAggregate struct {
 	AggregateID   string
 	Context       string
+	Key           string
 	ReferenceLang string
+	Description   string
 	Translations  []TranslationItem
+	Comments      []Comment
+	Attachments   []Attachment
+	Tags          []string
+	MaxChars      int
+	MaxGraphemes  int
+	IsArchived    bool
+	IsDeleted     bool
+	Version       int
 }
 
 const EMPTY_ID = "no-id"
@@ -54,7 +92,17 @@ func NewLocaleItemAggregate() LocaleItemAggregate {
 		EMPTY_ID,
 		EMPTY_CONTEXT,
 		"",
+		"",
+		"",
 		make([]TranslationItem, 0),
+		make([]Comment, 0),
+		make([]Attachment, 0),
+		make([]string, 0),
+		0,
+		0,
+		false,
+		false,
+		0,
 	}
 }
 
@@ -67,50 +115,139 @@ func (item *LocaleItemAggregate) GetTranslationItemByLang(lang string) (*Transla
 	return nil, fmt.Errorf("lang %s do not exist", lang)
 }
 
-func (item *LocaleItemAggregate) Reduce(evts []events.StoreEvent) {
+// Reduce applies events in order and stops on the first one that can not be decoded
+func (item *LocaleItemAggregate) Reduce(evts []events.StoreEvent) error {
 	for _, evt := range evts {
-		item.Apply(evt)
+		if err := item.Apply(evt); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (item *LocaleItemAggregate) Apply(evt events.StoreEvent) {
+func (item *LocaleItemAggregate) Apply(evt events.StoreEvent) error {
+	// version is the number of events in aggregate stream
+	item.Version++
+
+	// a deleted item is terminal: later events are ignored
+	if item.IsDeleted {
+		slog.Warn("event on deleted aggregate ignored", slog.String("aggregateId", item.AggregateID), slog.String("eventType", evt.EventType))
+		return nil
+	}
+
 	switch evt.EventType {
 	case domain.CreateLocaleItemStoreEventType:
-		item.init(evt)
+		return item.init(evt)
 	case domain.UpdateTranslationStoreEventType:
-		item.update(evt)
+		return item.update(evt)
+	case domain.RevertTranslationStoreEventType:
+		return item.revert(evt)
+	case domain.RemoveTranslationStoreEventType:
+		return item.removeTranslation(evt)
+	case domain.ChangeContextStoreEventType:
+		return item.changeContext(evt)
+	case domain.ChangeReferenceLangStoreEventType:
+		return item.changeReferenceLang(evt)
+	case domain.CanonicalizeLangStoreEventType:
+		return item.canonicalizeLang(evt)
+	case domain.RequestReviewTranslationStoreEventType:
+		return item.changeStatus(evt, domain.NeedsReviewTranslationStatus)
+	case domain.ApproveTranslationStoreEventType:
+		return item.changeStatus(evt, domain.ApprovedTranslationStatus)
+	case domain.RejectTranslationStoreEventType:
+		return item.changeStatus(evt, domain.RejectedTranslationStatus)
+	case domain.AddCommentStoreEventType:
+		return item.addComment(evt)
+	case domain.EditCommentStoreEventType:
+		return item.editComment(evt)
+	case domain.ResolveCommentStoreEventType:
+		return item.resolveComment(evt)
+	case domain.ChangeLengthLimitStoreEventType:
+		return item.changeLengthLimit(evt)
+	case domain.ChangeDescriptionStoreEventType:
+		return item.changeDescription(evt)
+	case domain.AddAttachmentStoreEventType:
+		return item.addAttachment(evt)
+	case domain.RemoveAttachmentStoreEventType:
+		return item.removeAttachment(evt)
+	case domain.AddTagStoreEventType:
+		return item.addTag(evt)
+	case domain.RemoveTagStoreEventType:
+		return item.removeTag(evt)
+	case domain.ArchiveLocaleItemStoreEventType:
+		item.IsArchived = true
+	case domain.RestoreLocaleItemStoreEventType:
+		item.IsArchived = false
+	case domain.DeleteLocaleItemStoreEventType:
+		item.IsDeleted = true
+	}
+	return nil
+}
+
+// eventTime returns when the event was stored so that replaying events gives the same aggregate
+func eventTime(evt events.StoreEvent) time.Time {
+	if evt.CreatedAt.IsZero() {
+		return time.Now().UTC()
 	}
+	return evt.CreatedAt.UTC()
 }
 
-func (item *LocaleItemAggregate) init(evt events.StoreEvent) {
-	createPayloadEvent, err := utils.DecodePayload[domain.CreateLocaleItemPayload](evt.PayloadData)
+func (item *LocaleItemAggregate) init(evt events.StoreEvent) error {
+	createPayloadEvent, err := domain.DecodePayload[domain.CreateLocaleItemPayload](evt)
 	if err != nil {
-		slog.Error("error on decode payload", slog.String("payloadDataType", evt.PayloadDataType))
+		return err
 	}
 	item.AggregateID = evt.AggregateID
 	item.Context = createPayloadEvent.Context
+	item.Key = createPayloadEvent.Key
 	item.ReferenceLang = createPayloadEvent.Lang
-	item.Translations = append(item.Translations, NewTranslationItem(
+	item.MaxChars = createPayloadEvent.MaxChars
+	item.MaxGraphemes = createPayloadEvent.MaxGraphemes
+	item.Description = createPayloadEvent.Description
+	translation := NewTranslationItem(
 		createPayloadEvent.Lang,
 		createPayloadEvent.Content,
-		createPayloadEvent.CreatedBy,
-	),
+		evt.CreatedBy,
 	)
+	translation.Plurals = createPayloadEvent.Plurals
+	translation.CreatedAt = eventTime(evt)
+	translation.UpdatedAt = eventTime(evt)
+	item.Translations = append(item.Translations, translation)
+	return nil
 }
 
-func (item *LocaleItemAggregate) update(evt events.StoreEvent) {
-	updatePayloadEvent, err := utils.DecodePayload[domain.UpdateTranslationLocaleItemPayload](evt.PayloadData)
+func (item *LocaleItemAggregate) update(evt events.StoreEvent) error {
+	updatePayloadEvent, err := domain.DecodePayload[domain.UpdateTranslationLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+	item.updateTranslation(evt, updatePayloadEvent)
+	return nil
+}
 
+func (item *LocaleItemAggregate) revert(evt events.StoreEvent) error {
+	revertPayloadEvent, err := domain.DecodePayload[domain.RevertTranslationLocaleItemPayload](evt)
 	if err != nil {
-		slog.Error("error on decode payload", slog.String("payloadDataType", evt.PayloadDataType))
+		return err
 	}
+	item.updateTranslation(evt, revertPayloadEvent.UpdateTranslationLocaleItemPayload)
+	return nil
+}
 
+// updateTranslation sets the content of the translation in the lang of updatePayloadEvent, adding it if missing
+func (item *LocaleItemAggregate) updateTranslation(evt events.StoreEvent, updatePayloadEvent domain.UpdateTranslationLocaleItemPayload) {
 	langFounded := false
+	referenceChanged := false
 	for i := 0; i < len(item.Translations); i++ {
 		t := &item.Translations[i]
 		if t.Lang == updatePayloadEvent.Lang {
+			referenceChanged = t.Lang == item.ReferenceLang &&
+				(t.Content != updatePayloadEvent.Content || !maps.Equal(t.Plurals, updatePayloadEvent.Plurals))
 			t.Content = updatePayloadEvent.Content
-			t.UpdatedAt = time.Now().UTC()
+			t.Plurals = updatePayloadEvent.Plurals
+			t.Status = domain.DraftTranslationStatus
+			t.IsStale = false
+			t.UpdatedAt = eventTime(evt)
 			t.UpdatedBy = evt.CreatedBy
 			langFounded = true
 			break
@@ -119,37 +256,241 @@ func (item *LocaleItemAggregate) update(evt events.StoreEvent) {
 
 	if !langFounded {
 		nt := NewTranslationItem(updatePayloadEvent.Lang, updatePayloadEvent.Content, "todo")
+		nt.Plurals = updatePayloadEvent.Plurals
+		nt.CreatedAt = eventTime(evt)
+		nt.UpdatedAt = eventTime(evt)
 		slog.Info("new translation item", slog.Any("translation", nt))
 		item.Translations = append(item.Translations, nt)
 	}
+
+	// other translations are out of date when reference text changes
+	if referenceChanged {
+		for i := 0; i < len(item.Translations); i++ {
+			if item.Translations[i].Lang != item.ReferenceLang {
+				item.Translations[i].IsStale = true
+			}
+		}
+	}
+}
+
+func (item *LocaleItemAggregate) removeTranslation(evt events.StoreEvent) error {
+	removePayloadEvent, err := domain.DecodePayload[domain.RemoveTranslationLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	// reference translation can not be removed until another reference is chosen
+	if removePayloadEvent.Lang == item.ReferenceLang {
+		slog.Warn("reference lang can not be removed", slog.String("aggregateId", item.AggregateID), slog.String("lang", removePayloadEvent.Lang))
+		return nil
+	}
+
+	for i := 0; i < len(item.Translations); i++ {
+		if item.Translations[i].Lang == removePayloadEvent.Lang {
+			item.Translations = append(item.Translations[:i], item.Translations[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (item *LocaleItemAggregate) changeContext(evt events.StoreEvent) error {
+	contextPayloadEvent, err := domain.DecodePayload[domain.ChangeContextLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	item.Context = contextPayloadEvent.Context
+	return nil
+}
+
+func (item *LocaleItemAggregate) changeReferenceLang(evt events.StoreEvent) error {
+	referencePayloadEvent, err := domain.DecodePayload[domain.ChangeReferenceLangLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	// new reference must be an existing translation
+	_, err = item.GetTranslationItemByLang(referencePayloadEvent.Lang)
+	if err != nil {
+		slog.Warn("reference lang without translation", slog.String("aggregateId", item.AggregateID), slog.String("lang", referencePayloadEvent.Lang))
+		return nil
+	}
+
+	item.ReferenceLang = referencePayloadEvent.Lang
+	for i := 0; i < len(item.Translations); i++ {
+		if item.Translations[i].Lang == item.ReferenceLang {
+			item.Translations[i].IsStale = false
+		}
+	}
+	return nil
+}
+
+// canonicalizeLang renames the translation and comments in a legacy lang to its canonical code; if
+// the item has already a translation in the canonical code, the one updated last is kept
+func (item *LocaleItemAggregate) canonicalizeLang(evt events.StoreEvent) error {
+	canonicalizePayloadEvent, err := domain.DecodePayload[domain.CanonicalizeLangLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+	from := canonicalizePayloadEvent.Lang
+	to := canonicalizePayloadEvent.CanonicalLang
+	if from == to {
+		return nil
+	}
+
+	fromIdx := slices.IndexFunc(item.Translations, func(t TranslationItem) bool { return t.Lang == from })
+	toIdx := slices.IndexFunc(item.Translations, func(t TranslationItem) bool { return t.Lang == to })
+	switch {
+	case fromIdx >= 0 && toIdx < 0:
+		item.Translations[fromIdx].Lang = to
+	case fromIdx >= 0 && toIdx >= 0:
+		if item.Translations[fromIdx].UpdatedAt.After(item.Translations[toIdx].UpdatedAt) {
+			item.Translations[fromIdx].Lang = to
+			item.Translations = slices.Delete(item.Translations, toIdx, toIdx+1)
+		} else {
+			item.Translations = slices.Delete(item.Translations, fromIdx, fromIdx+1)
+		}
+	}
+
+	if item.ReferenceLang == from {
+		item.ReferenceLang = to
+	}
+	for i := 0; i < len(item.Comments); i++ {
+		if item.Comments[i].Lang == from {
+			item.Comments[i].Lang = to
+		}
+	}
+	return nil
+}
+
+func (item *LocaleItemAggregate) changeDescription(evt events.StoreEvent) error {
+	descriptionPayloadEvent, err := domain.DecodePayload[domain.ChangeDescriptionLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	item.Description = descriptionPayloadEvent.Description
+	return nil
+}
+
+func (item *LocaleItemAggregate) changeLengthLimit(evt events.StoreEvent) error {
+	limitPayloadEvent, err := domain.DecodePayload[domain.ChangeLengthLimitLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	item.MaxChars = limitPayloadEvent.MaxChars
+	item.MaxGraphemes = limitPayloadEvent.MaxGraphemes
+	return nil
+}
+
+// HasTag reports if item is labeled with tag
+func (item *LocaleItemAggregate) HasTag(tag string) bool {
+	return slices.Contains(item.Tags, tag)
+}
+
+func (item *LocaleItemAggregate) addTag(evt events.StoreEvent) error {
+	tagPayloadEvent, err := domain.DecodePayload[domain.TagLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	if !item.HasTag(tagPayloadEvent.Tag) {
+		item.Tags = append(item.Tags, tagPayloadEvent.Tag)
+	}
+	return nil
+}
+
+func (item *LocaleItemAggregate) removeTag(evt events.StoreEvent) error {
+	tagPayloadEvent, err := domain.DecodePayload[domain.TagLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	item.Tags = slices.DeleteFunc(item.Tags, func(t string) bool {
+		return t == tagPayloadEvent.Tag
+	})
+	return nil
+}
+
+func (item *LocaleItemAggregate) changeStatus(evt events.StoreEvent, status string) error {
+	statusPayloadEvent, err := domain.DecodePayload[domain.TranslationStatusLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(item.Translations); i++ {
+		t := &item.Translations[i]
+		if t.Lang == statusPayloadEvent.Lang {
+			if !t.CanChangeStatus(status) {
+				slog.Warn("translation status transition not allowed",
+					slog.String("aggregateId", item.AggregateID),
+					slog.String("lang", t.Lang),
+					slog.String("from", t.Status),
+					slog.String("to", status),
+				)
+				return nil
+			}
+			t.Status = status
+			t.UpdatedAt = eventTime(evt)
+			t.UpdatedBy = evt.CreatedBy
+			return nil
+		}
+	}
+	return nil
 }
 
 type LocaleItemList struct {
-	Id              string    `db:"aggregate_id"`
-	Content         string    `db:"content"`
-	Context         string    `db:"context"`
-	Lang            string    `db:"lang"`
-	UpdatedAt       time.Time `db:"updated_at"`
-	UpdatedBy       string    `db:"updated_by"`
-	IsLangReference bool      `db:"is_lang_reference"`
+	Id              string       `db:"aggregate_id"`
+	Content         string       `db:"content"`
+	Plurals         plural.Forms `db:"plurals"`
+	Context         string       `db:"context"`
+	Key             string       `db:"key"`
+	Lang            string       `db:"lang"`
+	Status          string       `db:"status"`
+	UpdatedAt       time.Time    `db:"updated_at"`
+	UpdatedBy       string       `db:"updated_by"`
+	IsLangReference bool         `db:"is_lang_reference"`
+	IsStale         bool         `db:"is_stale"`
+	IsArchived      bool         `db:"is_archived"`
+	OpenComments    int          `db:"open_comments"`
+	MaxChars        int          `db:"max_chars"`
+	MaxGraphemes    int          `db:"max_graphemes"`
 }
 
 func NewLocaleItemList(
 	id string,
 	content string,
+	plurals plural.Forms,
 	context string,
+	key string,
 	lang string,
+	status string,
 	updatedAt time.Time,
 	updatedBy string,
 	isLangReference bool,
+	isStale bool,
+	isArchived bool,
+	openComments int,
+	maxChars int,
+	maxGraphemes int,
 ) LocaleItemList {
 	return LocaleItemList{
 		Id:              id,
 		Content:         content,
+		Plurals:         plurals,
 		Context:         context,
+		Key:             key,
 		Lang:            lang,
+		Status:          status,
 		UpdatedAt:       updatedAt,
 		UpdatedBy:       updatedBy,
 		IsLangReference: isLangReference,
+		IsStale:         isStale,
+		IsArchived:      isArchived,
+		OpenComments:    openComments,
+		MaxChars:        maxChars,
+		MaxGraphemes:    maxGraphemes,
 	}
 }
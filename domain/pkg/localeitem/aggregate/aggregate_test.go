@@ -0,0 +1,213 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+func TestReduceRevert(t *testing.T) {
+	create, err := domain.NewCreateEvent("hello", nil, "home", "en", "home.title", 0, 0, "", "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := create.AggregateID
+
+	newEvent := func(evt events.StoreEvent, err error) events.StoreEvent {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return evt
+	}
+
+	tests := []struct {
+		name    string
+		evts    []events.StoreEvent
+		content map[string]string
+		stale   map[string]bool
+	}{
+		{
+			name: "revert reference",
+			evts: []events.StoreEvent{
+				create,
+				newEvent(domain.NewUpdateEvent(id, "hi", nil, "en", "user")),
+				newEvent(domain.NewRevertEvent(id, "hello", nil, "en", 1, "user")),
+			},
+			content: map[string]string{"en": "hello"},
+		},
+		{
+			name: "update after revert",
+			evts: []events.StoreEvent{
+				create,
+				newEvent(domain.NewUpdateEvent(id, "hi", nil, "en", "user")),
+				newEvent(domain.NewRevertEvent(id, "hello", nil, "en", 1, "user")),
+				newEvent(domain.NewUpdateEvent(id, "hello there", nil, "en", "user")),
+			},
+			content: map[string]string{"en": "hello there"},
+		},
+		{
+			name: "revert translation then reference changes",
+			evts: []events.StoreEvent{
+				create,
+				newEvent(domain.NewUpdateEvent(id, "ciao", nil, "it", "user")),
+				newEvent(domain.NewUpdateEvent(id, "salve", nil, "it", "user")),
+				newEvent(domain.NewRevertEvent(id, "ciao", nil, "it", 2, "user")),
+				newEvent(domain.NewUpdateEvent(id, "hi", nil, "en", "user")),
+			},
+			content: map[string]string{"en": "hi", "it": "ciao"},
+			stale:   map[string]bool{"it": true},
+		},
+		{
+			name: "revert reference marks translations stale",
+			evts: []events.StoreEvent{
+				create,
+				newEvent(domain.NewUpdateEvent(id, "hi", nil, "en", "user")),
+				newEvent(domain.NewUpdateEvent(id, "ciao", nil, "it", "user")),
+				newEvent(domain.NewRevertEvent(id, "hello", nil, "en", 1, "user")),
+			},
+			content: map[string]string{"en": "hello", "it": "ciao"},
+			stale:   map[string]bool{"it": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := LocaleItemAggregate{}
+			err := item.Reduce(tt.evts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if item.Version != len(tt.evts) {
+				t.Errorf("got version %d, want %d", item.Version, len(tt.evts))
+			}
+			if len(item.Translations) != len(tt.content) {
+				t.Errorf("got %d translations, want %d", len(item.Translations), len(tt.content))
+			}
+			for lang, content := range tt.content {
+				translation, err := item.GetTranslationItemByLang(lang)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if translation.Content != content {
+					t.Errorf("got %s content %q, want %q", lang, translation.Content, content)
+				}
+				if translation.Status != domain.DraftTranslationStatus {
+					t.Errorf("got %s status %q, want %q", lang, translation.Status, domain.DraftTranslationStatus)
+				}
+				if translation.IsStale != tt.stale[lang] {
+					t.Errorf("got %s stale %v, want %v", lang, translation.IsStale, tt.stale[lang])
+				}
+			}
+		})
+	}
+}
+
+func TestReduceCanonicalizeLang(t *testing.T) {
+	create, err := domain.NewCreateEvent("hello", nil, "home", "en_US", "home.title", 0, 0, "", "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := create.AggregateID
+
+	// events are stored a minute apart, so that the translation updated last is known
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newEvents := func(evts ...events.StoreEvent) []events.StoreEvent {
+		for i := range evts {
+			evts[i].CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		}
+		return evts
+	}
+	newEvent := func(evt events.StoreEvent, err error) events.StoreEvent {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return evt
+	}
+
+	tests := []struct {
+		name      string
+		evts      []events.StoreEvent
+		content   map[string]string
+		reference string
+	}{
+		{
+			name: "reference lang",
+			evts: newEvents(
+				create,
+				newEvent(domain.NewCanonicalizeLangEvent(id, "en_US", "en-US", "system")),
+			),
+			content:   map[string]string{"en-US": "hello"},
+			reference: "en-US",
+		},
+		{
+			name: "translation lang",
+			evts: newEvents(
+				create,
+				newEvent(domain.NewUpdateEvent(id, "ciao", nil, "IT", "user")),
+				newEvent(domain.NewCanonicalizeLangEvent(id, "IT", "it", "system")),
+			),
+			content:   map[string]string{"en_US": "hello", "it": "ciao"},
+			reference: "en_US",
+		},
+		{
+			name: "canonical translation updated last is kept",
+			evts: newEvents(
+				create,
+				newEvent(domain.NewUpdateEvent(id, "hi", nil, "en-US", "user")),
+				newEvent(domain.NewCanonicalizeLangEvent(id, "en_US", "en-US", "system")),
+			),
+			content:   map[string]string{"en-US": "hi"},
+			reference: "en-US",
+		},
+		{
+			name: "legacy translation updated last is kept",
+			evts: newEvents(
+				create,
+				newEvent(domain.NewUpdateEvent(id, "hi", nil, "en-US", "user")),
+				newEvent(domain.NewUpdateEvent(id, "hello there", nil, "en_US", "user")),
+				newEvent(domain.NewCanonicalizeLangEvent(id, "en_US", "en-US", "system")),
+			),
+			content:   map[string]string{"en-US": "hello there"},
+			reference: "en-US",
+		},
+		{
+			name: "repeated",
+			evts: newEvents(
+				create,
+				newEvent(domain.NewCanonicalizeLangEvent(id, "en_US", "en-US", "system")),
+				newEvent(domain.NewCanonicalizeLangEvent(id, "en_US", "en-US", "system")),
+			),
+			content:   map[string]string{"en-US": "hello"},
+			reference: "en-US",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := LocaleItemAggregate{}
+			err := item.Reduce(tt.evts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if item.ReferenceLang != tt.reference {
+				t.Errorf("got reference lang %q, want %q", item.ReferenceLang, tt.reference)
+			}
+			if len(item.Translations) != len(tt.content) {
+				t.Errorf("got %d translations, want %d", len(item.Translations), len(tt.content))
+			}
+			for lang, content := range tt.content {
+				translation, err := item.GetTranslationItemByLang(lang)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if translation.Content != content {
+					t.Errorf("got %s content %q, want %q", lang, translation.Content, content)
+				}
+			}
+		})
+	}
+}
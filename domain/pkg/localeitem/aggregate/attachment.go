@@ -0,0 +1,63 @@
+package aggregate
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+// Attachment is a file, like a screenshot, giving context to translators; its content is in the blob store
+type Attachment struct {
+	Id          string
+	FileName    string
+	ContentType string
+	Size        int64
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+func (item *LocaleItemAggregate) GetAttachmentByID(id string) (*Attachment, error) {
+	for i := 0; i < len(item.Attachments); i++ {
+		if item.Attachments[i].Id == id {
+			return &item.Attachments[i], nil
+		}
+	}
+	return nil, fmt.Errorf("attachment %s do not exist", id)
+}
+
+// AttachmentBlobName returns the blob store name of the attachment content
+func AttachmentBlobName(aggregateID string, attachmentID string) string {
+	return aggregateID + "/" + attachmentID
+}
+
+func (item *LocaleItemAggregate) addAttachment(evt events.StoreEvent) error {
+	attachmentPayloadEvent, err := domain.DecodePayload[domain.AddAttachmentLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	item.Attachments = append(item.Attachments, Attachment{
+		Id:          attachmentPayloadEvent.AttachmentId,
+		FileName:    attachmentPayloadEvent.FileName,
+		ContentType: attachmentPayloadEvent.ContentType,
+		Size:        attachmentPayloadEvent.Size,
+		CreatedBy:   evt.CreatedBy,
+		CreatedAt:   eventTime(evt),
+	})
+	return nil
+}
+
+func (item *LocaleItemAggregate) removeAttachment(evt events.StoreEvent) error {
+	attachmentPayloadEvent, err := domain.DecodePayload[domain.RemoveAttachmentLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	item.Attachments = slices.DeleteFunc(item.Attachments, func(a Attachment) bool {
+		return a.Id == attachmentPayloadEvent.AttachmentId
+	})
+	return nil
+}
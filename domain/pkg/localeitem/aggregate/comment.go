@@ -0,0 +1,106 @@
+package aggregate
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+// Comment is a message on a locale item; replies have ParentId and only thread roots can be resolved
+type Comment struct {
+	Id         string
+	ParentId   string
+	Lang       string
+	Content    string
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	IsResolved bool
+	ResolvedBy string
+	ResolvedAt time.Time
+}
+
+func (item *LocaleItemAggregate) GetCommentByID(id string) (*Comment, error) {
+	for i := 0; i < len(item.Comments); i++ {
+		if item.Comments[i].Id == id {
+			return &item.Comments[i], nil
+		}
+	}
+	return nil, fmt.Errorf("comment %s do not exist", id)
+}
+
+// OpenThreads returns the number of unresolved threads for lang, counting also threads not scoped to a lang
+func (item *LocaleItemAggregate) OpenThreads(lang string) int {
+	result := 0
+	for _, c := range item.Comments {
+		if c.ParentId == "" && !c.IsResolved && (c.Lang == "" || c.Lang == lang) {
+			result++
+		}
+	}
+	return result
+}
+
+func (item *LocaleItemAggregate) addComment(evt events.StoreEvent) error {
+	commentPayloadEvent, err := domain.DecodePayload[domain.AddCommentLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	// replies belong to the parent thread lang
+	lang := commentPayloadEvent.Lang
+	if commentPayloadEvent.ParentId != "" {
+		parent, err := item.GetCommentByID(commentPayloadEvent.ParentId)
+		if err != nil {
+			slog.Warn("reply to unknown comment", slog.String("aggregateId", item.AggregateID), slog.String("parentId", commentPayloadEvent.ParentId))
+			return nil
+		}
+		lang = parent.Lang
+	}
+
+	item.Comments = append(item.Comments, Comment{
+		Id:        commentPayloadEvent.CommentId,
+		ParentId:  commentPayloadEvent.ParentId,
+		Lang:      lang,
+		Content:   commentPayloadEvent.Content,
+		CreatedBy: evt.CreatedBy,
+		CreatedAt: eventTime(evt),
+		UpdatedAt: eventTime(evt),
+	})
+	return nil
+}
+
+func (item *LocaleItemAggregate) editComment(evt events.StoreEvent) error {
+	commentPayloadEvent, err := domain.DecodePayload[domain.EditCommentLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	c, err := item.GetCommentByID(commentPayloadEvent.CommentId)
+	if err != nil {
+		slog.Warn("edit of unknown comment", slog.String("aggregateId", item.AggregateID), slog.String("commentId", commentPayloadEvent.CommentId))
+		return nil
+	}
+	c.Content = commentPayloadEvent.Content
+	c.UpdatedAt = eventTime(evt)
+	return nil
+}
+
+func (item *LocaleItemAggregate) resolveComment(evt events.StoreEvent) error {
+	commentPayloadEvent, err := domain.DecodePayload[domain.ResolveCommentLocaleItemPayload](evt)
+	if err != nil {
+		return err
+	}
+
+	c, err := item.GetCommentByID(commentPayloadEvent.CommentId)
+	if err != nil || c.ParentId != "" {
+		slog.Warn("resolve of unknown or reply comment", slog.String("aggregateId", item.AggregateID), slog.String("commentId", commentPayloadEvent.CommentId))
+		return nil
+	}
+	c.IsResolved = true
+	c.ResolvedBy = evt.CreatedBy
+	c.ResolvedAt = eventTime(evt)
+	return nil
+}
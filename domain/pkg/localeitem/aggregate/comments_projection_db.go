@@ -0,0 +1,205 @@
+package aggregate
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nats-io/nats.go"
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+type LocaleItemAggregateCommentsState struct {
+	repository *sqlx.DB
+	publisher  *nats.Conn
+}
+
+var LocaleItemAggregateCommentsAddress = actor.NewAddress("local", "comments-aggregate-persister")
+
+func NewLocaleItemAggregateCommentsState() (*LocaleItemAggregateCommentsState, error) {
+	db, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	natsToken := os.Getenv("NATS_SECRET")
+	nc, err := nats.Connect(nats.DefaultURL, nats.Token(natsToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocaleItemAggregateCommentsState{
+		repository: db,
+		publisher:  nc,
+	}, nil
+}
+
+type LocaleItemComment struct {
+	Id          string    `db:"comment_id"`
+	AggregateId string    `db:"aggregate_id"`
+	ParentId    string    `db:"parent_id"`
+	Lang        string    `db:"lang"`
+	Content     string    `db:"content"`
+	CreatedBy   string    `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	IsResolved  bool      `db:"is_resolved"`
+	ResolvedBy  string    `db:"resolved_by"`
+	ResolvedAt  time.Time `db:"resolved_at"`
+}
+
+func NewLocaleItemComment(aggregateId string, comment Comment) LocaleItemComment {
+	return LocaleItemComment{
+		Id:          comment.Id,
+		AggregateId: aggregateId,
+		ParentId:    comment.ParentId,
+		Lang:        comment.Lang,
+		Content:     comment.Content,
+		CreatedBy:   comment.CreatedBy,
+		CreatedAt:   comment.CreatedAt,
+		UpdatedAt:   comment.UpdatedAt,
+		IsResolved:  comment.IsResolved,
+		ResolvedBy:  comment.ResolvedBy,
+		ResolvedAt:  comment.ResolvedAt,
+	}
+}
+
+type AddLocaleItemAggregateCommentsBody struct {
+	Aggregate LocaleItemAggregate
+}
+
+// AddLocaleItemAggregateCommentsBatchBody persists the comments of many aggregates
+type AddLocaleItemAggregateCommentsBatchBody struct {
+	Aggregates []LocaleItemAggregate
+}
+
+// GetCommentsBody is the query message for the comments of an item; with Lang only the comments
+// scoped to it or to no lang are returned
+type GetCommentsBody struct {
+	AggregateId     string
+	Lang            string
+	IncludeResolved bool
+}
+
+type GetCommentsBodyResult struct {
+	Comments []LocaleItemComment
+}
+
+func (state *LocaleItemAggregateCommentsState) Process(msg actor.Message) {
+	switch payload := msg.Body.(type) {
+	case AddLocaleItemAggregateCommentsBody:
+		state.addHandler(payload.Aggregate)
+	case AddLocaleItemAggregateCommentsBatchBody:
+		for _, aggregate := range payload.Aggregates {
+			// most batches only create or update translations
+			if len(aggregate.Comments) == 0 && !aggregate.IsDeleted {
+				continue
+			}
+			state.addHandler(aggregate)
+		}
+	case GetCommentsBody:
+		result, err := state.getComments(payload)
+		if err != nil {
+			slog.Error("error on get comments", slog.String("err", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(GetCommentsBodyResult{Comments: result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	}
+}
+
+func (state *LocaleItemAggregateCommentsState) addHandler(aggregate LocaleItemAggregate) {
+	err := state.persistComments(aggregate)
+	if err != nil {
+		slog.Error("error on persist comments", slog.String("err", err.Error()))
+		return
+	}
+
+	err = state.publisher.Publish("locale.comments.updated", []byte(aggregate.AggregateID))
+	if err != nil {
+		slog.Error("error on publish comments updated", slog.String("err", err.Error()))
+	}
+}
+
+const commentsDeleteByAggregateID = `DELETE FROM locale.localeitem_comments WHERE aggregate_id = $1`
+
+var commentInsertOrUpdate string = `INSERT INTO locale.localeitem_comments (comment_id, aggregate_id, parent_id, lang, content, created_by, created_at, updated_at, is_resolved, resolved_by, resolved_at)
+VALUES (:comment_id, :aggregate_id, :parent_id, :lang, :content, :created_by, :created_at, :updated_at, :is_resolved, :resolved_by, :resolved_at)
+ON CONFLICT (comment_id)
+DO UPDATE SET
+    lang = :lang,
+    content = :content,
+    updated_at = :updated_at,
+    is_resolved = :is_resolved,
+    resolved_by = :resolved_by,
+    resolved_at = :resolved_at;
+`
+
+func (state *LocaleItemAggregateCommentsState) persistComments(aggregate LocaleItemAggregate) error {
+	// comments of deleted items are dropped
+	if aggregate.IsDeleted {
+		_, err := state.repository.Exec(commentsDeleteByAggregateID, aggregate.AggregateID)
+		return err
+	}
+
+	if len(aggregate.Comments) == 0 {
+		return nil
+	}
+
+	tx, err := state.repository.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction %w", err)
+	}
+
+	for _, c := range aggregate.Comments {
+		_, err = tx.NamedExec(commentInsertOrUpdate, NewLocaleItemComment(aggregate.AggregateID, c))
+		if err != nil {
+			if rberr := tx.Rollback(); rberr != nil {
+				slog.Error("transation fail so apply rollback", slog.String("error", rberr.Error()))
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (state *LocaleItemAggregateCommentsState) getComments(params GetCommentsBody) ([]LocaleItemComment, error) {
+	query := "SELECT * FROM locale.localeitem_comments WHERE aggregate_id = $1"
+	args := []any{params.AggregateId}
+	if params.Lang != "" {
+		args = append(args, params.Lang)
+		query += fmt.Sprintf(" AND (lang = '' OR lang = $%d)", len(args))
+	}
+	if !params.IncludeResolved {
+		// replies of open threads are kept
+		query += " AND is_resolved = false AND (parent_id = '' OR parent_id IN (SELECT comment_id FROM locale.localeitem_comments WHERE is_resolved = false))"
+	}
+	query += " ORDER BY created_at"
+
+	result := make([]LocaleItemComment, 0)
+	err := state.repository.Select(&result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (state *LocaleItemAggregateCommentsState) GetState() any {
+	return nil
+}
+
+func (state *LocaleItemAggregateCommentsState) Shutdown() {
+	err := state.repository.Close()
+	if err != nil {
+		slog.Error("fail to close repository", slog.String("error", err.Error()))
+	}
+	state.repository = nil
+
+	state.publisher.Close()
+	state.publisher = nil
+}
@@ -50,6 +50,11 @@ type AddLocaleItemAggregateDetailBody struct {
 	Aggregate LocaleItemAggregate
 }
 
+// AddLocaleItemAggregateDetailBatchBody persists many aggregates in one transaction
+type AddLocaleItemAggregateDetailBatchBody struct {
+	Aggregates []LocaleItemAggregate
+}
+
 type GetLocaleItemAggregateDetailBody struct {
 	Id string
 }
@@ -62,6 +67,8 @@ func (state *LocaleItemAggregateDetailState) Process(msg actor.Message) {
 	switch payload := msg.Body.(type) {
 	case AddLocaleItemAggregateDetailBody:
 		state.addDetail(payload.Aggregate)
+	case AddLocaleItemAggregateDetailBatchBody:
+		state.addDetails(payload.Aggregates)
 	case GetLocaleItemAggregateDetailBody:
 		result, err := state.getDetail(payload.Id)
 		if err != nil {
@@ -76,7 +83,12 @@ func (state *LocaleItemAggregateDetailState) Process(msg actor.Message) {
 }
 
 func (state *LocaleItemAggregateDetailState) addDetail(aggregate LocaleItemAggregate) {
-	err := state.persistDetail(aggregate)
+	if aggregate.IsDeleted {
+		state.removeDetail(aggregate)
+		return
+	}
+
+	err := state.persistDetail(state.repository, aggregate)
 	if err != nil {
 		slog.Error("error on persist detail", slog.String("err", err.Error()))
 		return
@@ -88,7 +100,58 @@ func (state *LocaleItemAggregateDetailState) addDetail(aggregate LocaleItemAggre
 	}
 }
 
-func (state *LocaleItemAggregateDetailState) persistDetail(aggregate LocaleItemAggregate) error {
+func (state *LocaleItemAggregateDetailState) addDetails(aggregates []LocaleItemAggregate) {
+	if len(aggregates) == 0 {
+		return
+	}
+
+	err := state.persistDetails(aggregates)
+	if err != nil {
+		slog.Error("error on persist details", slog.String("err", err.Error()))
+		return
+	}
+
+	for _, aggregate := range aggregates {
+		subject := "locale.detail.updated"
+		if aggregate.IsDeleted {
+			subject = "locale.detail.deleted"
+		}
+		err = state.publisher.Publish(subject, []byte(aggregate.AggregateID))
+		if err != nil {
+			slog.Error("error on publish detail", slog.String("subject", subject), slog.String("err", err.Error()))
+		}
+	}
+}
+
+func (state *LocaleItemAggregateDetailState) persistDetails(aggregates []LocaleItemAggregate) (err error) {
+	tx, err := state.repository.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rberr := tx.Rollback(); rberr != nil {
+				slog.Error("transation fail so apply rollback", slog.String("error", rberr.Error()))
+			}
+		}
+	}()
+
+	for _, aggregate := range aggregates {
+		if aggregate.IsDeleted {
+			_, err = tx.Exec(deleteDetailByID, aggregate.AggregateID)
+		} else {
+			err = state.persistDetail(tx, aggregate)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (state *LocaleItemAggregateDetailState) persistDetail(e sqlx.Ext, aggregate LocaleItemAggregate) error {
 	datajson, err := json.Marshal(aggregate)
 	if err != nil {
 		slog.Warn("fail to marshal aggregate",
@@ -98,7 +161,7 @@ func (state *LocaleItemAggregateDetailState) persistDetail(aggregate LocaleItemA
 		return err
 	}
 
-	res, err := state.repository.NamedExec(detailInsertOrUpdate, map[string]any{
+	res, err := sqlx.NamedExec(e, detailInsertOrUpdate, map[string]any{
 		"id":     aggregate.AggregateID,
 		"data":   string(datajson),
 		"upDate": time.Now().UTC(),
@@ -122,6 +185,21 @@ func (state *LocaleItemAggregateDetailState) persistDetail(aggregate LocaleItemA
 	return nil
 }
 
+const deleteDetailByID = `DELETE FROM locale.localeitem_detail WHERE aggregateid = $1`
+
+func (state *LocaleItemAggregateDetailState) removeDetail(aggregate LocaleItemAggregate) {
+	_, err := state.repository.Exec(deleteDetailByID, aggregate.AggregateID)
+	if err != nil {
+		slog.Error("error on delete detail", slog.String("err", err.Error()))
+		return
+	}
+
+	err = state.publisher.Publish("locale.detail.deleted", []byte(aggregate.AggregateID))
+	if err != nil {
+		slog.Error("error on publish detail deleted", slog.String("err", err.Error()))
+	}
+}
+
 const selectDetailByID = `SELECT data FROM locale.localeitem_detail WHERE aggregateid = $1`
 
 func (state *LocaleItemAggregateDetailState) getDetail(id string) (LocaleItemAggregate, error) {
@@ -0,0 +1,211 @@
+package aggregate
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+// LocaleItemEventRepository reads locale item events from the event store table; events are appended
+// through the event store actor or, for batches that must be stored all or none, by the locale item
+// writer in one transaction
+type LocaleItemEventRepository struct {
+	repository *sqlx.DB
+}
+
+func NewLocaleItemEventRepository() (*LocaleItemEventRepository, error) {
+	db, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &LocaleItemEventRepository{repository: db}, nil
+}
+
+const eventsCountByAggregateID = `SELECT count(*) FROM store.events WHERE aggregateid = $1`
+
+// Version returns the current version of aggregate as the number of its stored events
+func (repo *LocaleItemEventRepository) Version(aggregateID string) (int, error) {
+	var result int
+	err := repo.repository.Get(&result, eventsCountByAggregateID, aggregateID)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+const eventsCountByAggregateIDAt = `SELECT count(*) FROM store.events WHERE aggregateid = $1 AND createdat <= $2`
+
+// VersionAt returns the version aggregate had at time at
+func (repo *LocaleItemEventRepository) VersionAt(aggregateID string, at time.Time) (int, error) {
+	var result int
+	err := repo.repository.Get(&result, eventsCountByAggregateIDAt, aggregateID, at)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+const eventsByAggregateIDAfterVersion = `SELECT * FROM store.events WHERE aggregateid = $1 ORDER BY id OFFSET $2 LIMIT $3`
+
+// RetriveAfterVersion returns the events of aggregate after version up to toVersion, oldest first
+func (repo *LocaleItemEventRepository) RetriveAfterVersion(aggregateID string, version int, toVersion int) ([]events.StoreEvent, error) {
+	result := make([]events.StoreEvent, 0)
+	if toVersion <= version {
+		return result, nil
+	}
+	err := repo.repository.Select(&result, eventsByAggregateIDAfterVersion, aggregateID, version, toVersion-version)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// VersionedEvent is a stored event with the aggregate version it leads to
+type VersionedEvent struct {
+	events.StoreEvent
+	Version int `db:"version"`
+}
+
+const eventsByAggregateIDAndTypes = `SELECT * FROM (
+	SELECT *, row_number() OVER (ORDER BY id) AS version FROM store.events WHERE aggregateid = ?
+) AS e WHERE eventtype IN (?) ORDER BY id`
+
+// RetriveByEventTypes returns the events of aggregate of the given types with their version, oldest first
+func (repo *LocaleItemEventRepository) RetriveByEventTypes(aggregateID string, eventTypes []string) ([]VersionedEvent, error) {
+	query, args, err := sqlx.In(eventsByAggregateIDAndTypes, aggregateID, eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]VersionedEvent, 0)
+	err = repo.repository.Select(&result, repo.repository.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const eventsCountByAggregateIDs = `SELECT aggregateid, count(*) AS version FROM store.events WHERE aggregateid IN (?) GROUP BY aggregateid`
+
+type aggregateVersion struct {
+	AggregateID string `db:"aggregateid"`
+	Version     int    `db:"version"`
+}
+
+// Versions returns in tx the current version of the aggregates with events among aggregateIDs
+func (repo *LocaleItemEventRepository) Versions(tx *sqlx.Tx, aggregateIDs []string) (map[string]int, error) {
+	result := make(map[string]int)
+	if len(aggregateIDs) == 0 {
+		return result, nil
+	}
+	query, args, err := sqlx.In(eventsCountByAggregateIDs, aggregateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]aggregateVersion, 0)
+	err = tx.Select(&versions, tx.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		result[v.AggregateID] = v.Version
+	}
+	return result, nil
+}
+
+const eventsAfterSnapshotByAggregateIDs = `SELECT * FROM (
+	SELECT *, row_number() OVER (PARTITION BY aggregateid ORDER BY id) AS version FROM store.events WHERE aggregateid IN (?)
+) AS e WHERE version > COALESCE((
+	SELECT s.version FROM locale.localeitem_snapshot AS s WHERE s.aggregate_id = e.aggregateid AND s.schema_version = ?
+), 0) ORDER BY id`
+
+// RetriveAfterSnapshots returns the events of the aggregates after their latest snapshot, all of them
+// if they have none, with their version, oldest first
+func (repo *LocaleItemEventRepository) RetriveAfterSnapshots(aggregateIDs []string) ([]VersionedEvent, error) {
+	result := make([]VersionedEvent, 0)
+	if len(aggregateIDs) == 0 {
+		return result, nil
+	}
+	query, args, err := sqlx.In(eventsAfterSnapshotByAggregateIDs, aggregateIDs, SnapshotSchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.repository.Select(&result, repo.repository.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Begin starts the transaction appending a batch of events
+func (repo *LocaleItemEventRepository) Begin() (*sqlx.Tx, error) {
+	return repo.repository.Beginx()
+}
+
+type storeEventRow struct {
+	AggregateID     string    `db:"aggregateid"`
+	AggregateName   string    `db:"aggregatename"`
+	CreatedAt       time.Time `db:"createdat"`
+	CreatedBy       string    `db:"createdby"`
+	EventType       string    `db:"eventtype"`
+	PayloadData     string    `db:"payloaddata"`
+	PayloadDataType string    `db:"payloaddatatype"`
+}
+
+const storeEventInsert = `INSERT INTO store.events (aggregateId, aggregateName, createdAt, createdBy, eventType, payloadData, payloadDataType)
+VALUES (:aggregateid, :aggregatename, :createdat, :createdby, :eventtype, :payloaddata, :payloaddatatype)`
+
+// appendChunk is the number of events inserted by one statement, within the postgres parameters limit
+const appendChunk = 1000
+
+// Append inserts evts in tx in order
+func (repo *LocaleItemEventRepository) Append(tx *sqlx.Tx, evts []events.StoreEvent) error {
+	for start := 0; start < len(evts); start += appendChunk {
+		chunk := evts[start:min(start+appendChunk, len(evts))]
+		rows := make([]storeEventRow, 0, len(chunk))
+		for _, evt := range chunk {
+			rows = append(rows, storeEventRow{
+				AggregateID:     evt.AggregateID,
+				AggregateName:   evt.AggregateName,
+				CreatedAt:       evt.CreatedAt,
+				CreatedBy:       evt.CreatedBy,
+				EventType:       evt.EventType,
+				PayloadData:     evt.PayloadData,
+				PayloadDataType: evt.PayloadDataType,
+			})
+		}
+		_, err := tx.NamedExec(storeEventInsert, rows)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LocaleItemLang is a lang used by a locale item in a translation or comment
+type LocaleItemLang struct {
+	AggregateID string `db:"aggregate_id"`
+	Lang        string `db:"lang"`
+}
+
+const langsOfItems = `SELECT aggregate_id, lang FROM locale.localeitems_list
+UNION SELECT aggregate_id, lang FROM locale.localeitem_comments WHERE lang <> ''
+ORDER BY aggregate_id, lang`
+
+// RetriveLangs returns the langs of translations and comments of every item, from their projections
+func (repo *LocaleItemEventRepository) RetriveLangs() ([]LocaleItemLang, error) {
+	result := make([]LocaleItemLang, 0)
+	err := repo.repository.Select(&result, langsOfItems)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (repo *LocaleItemEventRepository) Close() error {
+	return repo.repository.Close()
+}
@@ -0,0 +1,86 @@
+package aggregate
+
+import (
+	"time"
+
+	"github.com/pix303/localemgmt-go/domain/pkg/diff"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+// HistoryEntry is a content change of a translation; RevertedToVersion is set for reverts
+type HistoryEntry struct {
+	Version           int
+	EventType         string
+	Lang              string
+	CreatedBy         string
+	CreatedAt         time.Time
+	OldContent        string
+	NewContent        string
+	Diff              []diff.Op
+	RevertedToVersion int
+}
+
+// HistoryEventTypes are the types of the events changing translation contents
+var HistoryEventTypes = []string{
+	domain.CreateLocaleItemStoreEventType,
+	domain.UpdateTranslationStoreEventType,
+	domain.RevertTranslationStoreEventType,
+	domain.RemoveTranslationStoreEventType,
+}
+
+// NewHistory turns the aggregate events in the timeline of translation content changes, oldest first;
+// events of other types than HistoryEventTypes are ignored
+func NewHistory(evts []VersionedEvent) ([]HistoryEntry, error) {
+	result := make([]HistoryEntry, 0)
+	contents := make(map[string]string)
+
+	for _, versioned := range evts {
+		evt := versioned.StoreEvent
+		var lang, content string
+		var revertedToVersion int
+		switch evt.EventType {
+		case domain.CreateLocaleItemStoreEventType:
+			payload, err := domain.DecodePayload[domain.CreateLocaleItemPayload](evt)
+			if err != nil {
+				return nil, err
+			}
+			lang, content = payload.Lang, payload.Content
+		case domain.UpdateTranslationStoreEventType:
+			payload, err := domain.DecodePayload[domain.UpdateTranslationLocaleItemPayload](evt)
+			if err != nil {
+				return nil, err
+			}
+			lang, content = payload.Lang, payload.Content
+		case domain.RevertTranslationStoreEventType:
+			payload, err := domain.DecodePayload[domain.RevertTranslationLocaleItemPayload](evt)
+			if err != nil {
+				return nil, err
+			}
+			lang, content, revertedToVersion = payload.Lang, payload.Content, payload.RevertedToVersion
+		case domain.RemoveTranslationStoreEventType:
+			payload, err := domain.DecodePayload[domain.RemoveTranslationLocaleItemPayload](evt)
+			if err != nil {
+				return nil, err
+			}
+			lang = payload.Lang
+		default:
+			continue
+		}
+
+		oldContent := contents[lang]
+		contents[lang] = content
+		result = append(result, HistoryEntry{
+			Version:           versioned.Version,
+			EventType:         evt.EventType,
+			Lang:              lang,
+			CreatedBy:         evt.CreatedBy,
+			CreatedAt:         evt.CreatedAt,
+			OldContent:        oldContent,
+			NewContent:        content,
+			Diff:              diff.Words(oldContent, content),
+			RevertedToVersion: revertedToVersion,
+		})
+	}
+
+	return result, nil
+}
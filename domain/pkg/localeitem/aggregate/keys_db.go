@@ -0,0 +1,128 @@
+package aggregate
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+// LocaleItemKeyRepository has the context and key of each locale item; the locale item writer keeps
+// it with their events, so keys are unique in context against all stored items
+type LocaleItemKeyRepository struct {
+	repository *sqlx.DB
+}
+
+func NewLocaleItemKeyRepository() (*LocaleItemKeyRepository, error) {
+	db, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &LocaleItemKeyRepository{repository: db}, nil
+}
+
+// changesKey reports whether events of eventType change the context or key of their item
+func changesKey(eventType string) bool {
+	switch eventType {
+	case domain.CreateLocaleItemStoreEventType, domain.ChangeContextStoreEventType, domain.DeleteLocaleItemStoreEventType:
+		return true
+	}
+	return false
+}
+
+// Begin starts the transaction of the key changes of an event
+func (repo *LocaleItemKeyRepository) Begin() (*sqlx.Tx, error) {
+	return repo.repository.Beginx()
+}
+
+const (
+	keyInsert          = `INSERT INTO locale.localeitem_keys (aggregate_id, context, key) VALUES ($1, $2, $3)`
+	keyUpdateContext   = `UPDATE locale.localeitem_keys SET context = $2 WHERE aggregate_id = $1`
+	keyDelete          = `DELETE FROM locale.localeitem_keys WHERE aggregate_id = $1`
+	keyByAggregateID   = `SELECT key FROM locale.localeitem_keys WHERE aggregate_id = $1`
+	keyUsedByOtherItem = `SELECT count(*) FROM locale.localeitem_keys WHERE context = $1 AND key = $2 AND aggregate_id <> $3`
+)
+
+// Apply changes in tx the context and key of the item of evt as evt does; ErrKeyConflict if another
+// item uses the key in the item context
+func (repo *LocaleItemKeyRepository) Apply(tx *sqlx.Tx, evt events.StoreEvent) error {
+	switch evt.EventType {
+	case domain.CreateLocaleItemStoreEventType:
+		payload, err := domain.DecodePayload[domain.CreateLocaleItemPayload](evt)
+		if err != nil {
+			return err
+		}
+		err = verifyKeyAvailable(tx, evt.AggregateID, payload.Context, payload.Key)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(keyInsert, evt.AggregateID, payload.Context, payload.Key)
+		return err
+
+	case domain.ChangeContextStoreEventType:
+		payload, err := domain.DecodePayload[domain.ChangeContextLocaleItemPayload](evt)
+		if err != nil {
+			return err
+		}
+		var key string
+		err = tx.Get(&key, keyByAggregateID, evt.AggregateID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = verifyKeyAvailable(tx, evt.AggregateID, payload.Context, key)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(keyUpdateContext, evt.AggregateID, payload.Context)
+		return err
+
+	case domain.DeleteLocaleItemStoreEventType:
+		_, err := tx.Exec(keyDelete, evt.AggregateID)
+		return err
+	}
+	return nil
+}
+
+// LocaleItemContext is the context of a locale item
+type LocaleItemContext struct {
+	AggregateID string `db:"aggregate_id"`
+	Context     string `db:"context"`
+}
+
+const keysInContextSubtree = `SELECT aggregate_id, context FROM locale.localeitem_keys
+WHERE context = $1 OR left(context, length($1) + 1) = $1 || '/' ORDER BY aggregate_id`
+
+// RetriveInSubtree returns the context of the items in context or in its descendants
+func (repo *LocaleItemKeyRepository) RetriveInSubtree(context string) ([]LocaleItemContext, error) {
+	result := make([]LocaleItemContext, 0)
+	err := repo.repository.Select(&result, keysInContextSubtree, context)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func verifyKeyAvailable(tx *sqlx.Tx, aggregateID string, context string, key string) error {
+	if key == "" {
+		return nil
+	}
+	var used int
+	err := tx.Get(&used, keyUsedByOtherItem, context, key, aggregateID)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return ErrKeyConflict
+	}
+	return nil
+}
+
+func (repo *LocaleItemKeyRepository) Close() error {
+	return repo.repository.Close()
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/nats-io/nats.go"
 	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/localemgmt-go/domain/pkg/contexts"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
 	"github.com/pix303/postgres-util-go/pkg/postgres"
 )
 
@@ -40,20 +43,71 @@ type AddLocaleItemAggregateListBody struct {
 	Aggregate LocaleItemAggregate
 }
 
+// GetContextBody is the query message for the translations of context Id; with Subtree the ones
+// of its descendant contexts too
+// AddLocaleItemAggregateListBatchBody persists the translations of many aggregates in one transaction
+type AddLocaleItemAggregateListBatchBody struct {
+	Aggregates []LocaleItemAggregate
+}
+
 type GetContextBody struct {
-	Id string
+	Id              string
+	Subtree         bool
+	IncludeArchived bool
+	Status          string
+	StaleOnly       bool
+	Tags            []string
 }
 
 type GetContextBodyResult struct {
 	Items []LocaleItemList
 }
 
+// GetContextTreeBody is the query message for the translation counts by context and lang of the
+// subtree of context Root; an empty Root is the whole tree
+type GetContextTreeBody struct {
+	Root            string
+	IncludeArchived bool
+}
+
+type GetContextTreeBodyResult struct {
+	Counts []contexts.Count
+}
+
+// GetByKeyBody is the query message to get the translations of the item with key in context
+type GetByKeyBody struct {
+	Context string
+	Key     string
+}
+
+type GetByKeyBodyResult struct {
+	Items []LocaleItemList
+}
+
+// SearchBody is the query message to search translations by context, lang, partial content and
+// tags; items must have all Tags
+type SearchBody struct {
+	Context        string
+	Lang           string
+	PartialContent string
+	Page           int
+	PageSize       int
+	SortAsc        bool
+	Tags           []string
+}
+
+type SearchBodyResult struct {
+	Items []LocaleItemList
+}
+
 func (state *LocaleItemAggregateListState) Process(msg actor.Message) {
 	switch payload := msg.Body.(type) {
 	case AddLocaleItemAggregateListBody:
 		state.addHandler(payload.Aggregate)
+	case AddLocaleItemAggregateListBatchBody:
+		state.addBatchHandler(payload.Aggregates)
 	case GetContextBody:
-		result, err := state.getList(payload.Id)
+		result, err := state.getList(payload)
 		if err != nil {
 			slog.Error("error on persist list", slog.String("err", err.Error()))
 		}
@@ -61,33 +115,127 @@ func (state *LocaleItemAggregateListState) Process(msg actor.Message) {
 			returnMsg := actor.NewReturnMessage(GetContextBodyResult{Items: result}, msg)
 			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
 		}
+	case GetContextTreeBody:
+		result, err := state.getTreeCounts(payload)
+		if err != nil {
+			slog.Error("error on get context tree", slog.String("err", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(GetContextTreeBodyResult{Counts: result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	case GetByKeyBody:
+		result, err := state.getByKey(payload.Context, payload.Key)
+		if err != nil {
+			slog.Error("error on get by key", slog.String("err", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(GetByKeyBodyResult{Items: result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	case SearchBody:
+		result, err := state.search(payload)
+		if err != nil {
+			slog.Error("error on search list", slog.String("err", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(SearchBodyResult{Items: result}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
 	}
 }
 
 func (state *LocaleItemAggregateListState) addHandler(aggregate LocaleItemAggregate) {
-	err := state.persistList(aggregate)
+	// contexts where the rows are before persisting: they differ from the aggregate one if item is moved
+	previousContexts, err := state.getContextsByAggregateID(aggregate.AggregateID)
+	if err != nil {
+		slog.Error("error on retrive previous contexts", slog.String("err", err.Error()))
+	}
+
+	err = state.persistList(aggregate)
 	if err != nil {
 		slog.Error("error on persist list", slog.String("err", err.Error()))
 		return
 	}
 
-	err = state.publisher.Publish("locale.list.context.updated", []byte(aggregate.Context))
+	state.publishContextUpdated(aggregate.Context)
+	for _, c := range previousContexts {
+		if c != aggregate.Context {
+			state.publishContextUpdated(c)
+		}
+	}
+}
+
+// addBatchHandler persists all aggregates and publishes each updated context once
+func (state *LocaleItemAggregateListState) addBatchHandler(aggregates []LocaleItemAggregate) {
+	if len(aggregates) == 0 {
+		return
+	}
+
+	contexts := make(map[string]bool)
+	for _, aggregate := range aggregates {
+		previousContexts, err := state.getContextsByAggregateID(aggregate.AggregateID)
+		if err != nil {
+			slog.Error("error on retrive previous contexts", slog.String("err", err.Error()))
+		}
+		for _, c := range previousContexts {
+			contexts[c] = true
+		}
+		contexts[aggregate.Context] = true
+	}
+
+	err := state.persistList(aggregates...)
+	if err != nil {
+		slog.Error("error on persist list", slog.String("err", err.Error()))
+		return
+	}
+
+	for c := range contexts {
+		state.publishContextUpdated(c)
+	}
+}
+
+func (state *LocaleItemAggregateListState) publishContextUpdated(context string) {
+	err := state.publisher.Publish("locale.list.context.updated", []byte(context))
 	if err != nil {
 		slog.Error("error on publish list updated", slog.String("err", err.Error()))
 	}
 }
 
-var listitemInsertOrUpdate string = `INSERT INTO locale.localeitems_list (aggregate_id, lang, content, context, updated_at, updated_by, is_lang_reference)
-VALUES (:aggregate_id, :lang, :content, :context, :updated_at, :updated_by, :is_lang_reference)
+const listitemContextsByAggregateID = `SELECT DISTINCT context FROM locale.localeitems_list WHERE aggregate_id = $1`
+
+func (state *LocaleItemAggregateListState) getContextsByAggregateID(aggregateID string) ([]string, error) {
+	result := make([]string, 0)
+	err := state.repository.Select(&result, listitemContextsByAggregateID, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var listitemInsertOrUpdate string = `INSERT INTO locale.localeitems_list (aggregate_id, lang, content, plurals, context, key, status, updated_at, updated_by, is_lang_reference, is_stale, is_archived, open_comments, max_chars, max_graphemes)
+VALUES (:aggregate_id, :lang, :content, :plurals, :context, :key, :status, :updated_at, :updated_by, :is_lang_reference, :is_stale, :is_archived, :open_comments, :max_chars, :max_graphemes)
 ON CONFLICT (aggregate_id, lang )
 DO UPDATE SET
     content = :content,
+    plurals = :plurals,
+    context = :context,
+    key = :key,
+    status = :status,
     updated_at = :updated_at,
     updated_by = :updated_by,
-    is_lang_reference = :is_lang_reference;
+    is_lang_reference = :is_lang_reference,
+    is_stale = :is_stale,
+    is_archived = :is_archived,
+    open_comments = :open_comments,
+    max_chars = :max_chars,
+    max_graphemes = :max_graphemes;
 `
 
-func (state *LocaleItemAggregateListState) persistList(aggregate LocaleItemAggregate) error {
+const listitemDeleteRemovedLangs = `DELETE FROM locale.localeitems_list WHERE aggregate_id = ? AND lang NOT IN (?)`
+
+// persistList stores the translations of all aggregates in one transaction; rows of deleted aggregates are removed
+func (state *LocaleItemAggregateListState) persistList(aggregates ...LocaleItemAggregate) (err error) {
 
 	slog.Debug("start insert or update aggregate translations in list projection")
 	tx, err := state.repository.Beginx()
@@ -104,6 +252,40 @@ func (state *LocaleItemAggregateListState) persistList(aggregate LocaleItemAggre
 		}
 	}()
 
+	for _, aggregate := range aggregates {
+		if aggregate.IsDeleted {
+			err = removeList(tx, aggregate)
+		} else {
+			err = persistTranslations(tx, aggregate)
+			if err == nil {
+				err = persistTags(tx, aggregate)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	slog.Debug("finish insert or update aggregate translations in list projection")
+	return tx.Commit()
+}
+
+func persistTranslations(tx *sqlx.Tx, aggregate LocaleItemAggregate) error {
+	// remove rows of translations no longer in aggregate
+	langs := make([]string, 0, len(aggregate.Translations))
+	for _, tItem := range aggregate.Translations {
+		langs = append(langs, tItem.Lang)
+	}
+	deleteQuery, deleteArgs, err := sqlx.In(listitemDeleteRemovedLangs, aggregate.AggregateID, langs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(tx.Rebind(deleteQuery), deleteArgs...)
+	if err != nil {
+		slog.Error("fail delete statement of removed translations", slog.String("id", aggregate.AggregateID), slog.String("err", err.Error()))
+		return err
+	}
+
 	for _, tItem := range aggregate.Translations {
 		user := tItem.UpdatedBy
 		if user == "" {
@@ -113,14 +295,27 @@ func (state *LocaleItemAggregateListState) persistList(aggregate LocaleItemAggre
 			}
 		}
 
+		status := tItem.Status
+		if status == "" {
+			status = domain.DraftTranslationStatus
+		}
+
 		params := NewLocaleItemList(
 			aggregate.AggregateID,
-			tItem.Lang,
 			tItem.Content,
+			tItem.Plurals,
 			aggregate.Context,
+			aggregate.Key,
+			tItem.Lang,
+			status,
 			tItem.UpdatedAt,
 			user,
 			aggregate.ReferenceLang == tItem.Lang,
+			tItem.IsStale,
+			aggregate.IsArchived,
+			aggregate.OpenThreads(tItem.Lang),
+			aggregate.MaxChars,
+			aggregate.MaxGraphemes,
 		)
 		_, err = tx.NamedExec(listitemInsertOrUpdate, params)
 
@@ -134,13 +329,175 @@ func (state *LocaleItemAggregateListState) persistList(aggregate LocaleItemAggre
 		}
 	}
 
-	slog.Debug("finish insert or update aggregate translations in list projection")
-	return tx.Commit()
+	return nil
+}
+
+const tagsDeleteByAggregateID = `DELETE FROM locale.localeitem_tags WHERE aggregate_id = $1`
+
+const tagInsert = `INSERT INTO locale.localeitem_tags (aggregate_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+// persistTags replaces the tags of aggregate
+func persistTags(tx *sqlx.Tx, aggregate LocaleItemAggregate) error {
+	_, err := tx.Exec(tagsDeleteByAggregateID, aggregate.AggregateID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range aggregate.Tags {
+		_, err = tx.Exec(tagInsert, aggregate.AggregateID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const listitemDeleteByAggregateID = `DELETE FROM locale.localeitems_list WHERE aggregate_id = $1`
+
+func removeList(tx *sqlx.Tx, aggregate LocaleItemAggregate) error {
+	_, err := tx.Exec(listitemDeleteByAggregateID, aggregate.AggregateID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(tagsDeleteByAggregateID, aggregate.AggregateID)
+	return err
+}
+
+// tagsCondition returns the condition matching the rows of items with all tags, whose values are
+// appended to args
+func tagsCondition(tags []string, args []any) (string, []any) {
+	conditions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		args = append(args, tag)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM locale.localeitem_tags t WHERE t.aggregate_id = localeitems_list.aggregate_id AND t.tag = $%d)",
+			len(args),
+		))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// contextCondition selects the rows of context or, with subtree, of its descendants too
+func contextCondition(context string, subtree bool, args []any) (string, []any) {
+	args = append(args, context)
+	if !subtree {
+		return fmt.Sprintf("context = $%d", len(args)), args
+	}
+	args = append(args, likeEscaper.Replace(context+contexts.PathSeparator)+"%")
+	return fmt.Sprintf("(context = $%d OR context LIKE $%d)", len(args)-1, len(args)), args
+}
+
+func (state *LocaleItemAggregateListState) getList(params GetContextBody) ([]LocaleItemList, error) {
+	condition, args := contextCondition(params.Id, params.Subtree, make([]any, 0))
+	query := "SELECT * FROM locale.localeitems_list WHERE " + condition
+	if !params.IncludeArchived {
+		query += " AND is_archived = false"
+	}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if params.StaleOnly {
+		query += " AND is_stale = true"
+	}
+	if len(params.Tags) > 0 {
+		var condition string
+		condition, args = tagsCondition(params.Tags, args)
+		query += " AND " + condition
+	}
+
+	result := make([]LocaleItemList, 0)
+	err := state.repository.Select(&result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getTreeCounts returns the translation counts by context and lang of the contexts in the subtree of root;
+// items are counted on their reference translation
+func (state *LocaleItemAggregateListState) getTreeCounts(params GetContextTreeBody) ([]contexts.Count, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	if params.Root != "" {
+		var condition string
+		condition, args = contextCondition(params.Root, true, args)
+		conditions = append(conditions, condition)
+	}
+	if !params.IncludeArchived {
+		conditions = append(conditions, "is_archived = false")
+	}
+
+	query := "SELECT context, lang, count(*) FILTER (WHERE is_lang_reference) AS items, count(*) AS translations FROM locale.localeitems_list"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " GROUP BY context, lang ORDER BY context, lang;"
+
+	result := make([]contexts.Count, 0)
+	err := state.repository.Select(&result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const listitemByContextAndKey = `SELECT * FROM locale.localeitems_list WHERE context = $1 AND key = $2`
+
+func (state *LocaleItemAggregateListState) getByKey(context string, key string) ([]LocaleItemList, error) {
+	result := make([]LocaleItemList, 0)
+	err := state.repository.Select(&result, listitemByContextAndKey, context, key)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (state *LocaleItemAggregateListState) getList(context string) ([]LocaleItemList, error) {
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// search returns the translations matching all the given filters; partial content is matched
+// case insensitive as substring, by trigram similarity or by full text
+func (state *LocaleItemAggregateListState) search(params SearchBody) ([]LocaleItemList, error) {
+	conditions := []string{"is_archived = false"}
+	args := make([]any, 0)
+
+	if params.Context != "" {
+		args = append(args, params.Context)
+		conditions = append(conditions, fmt.Sprintf("context = $%d", len(args)))
+	}
+
+	if params.Lang != "" {
+		args = append(args, params.Lang)
+		conditions = append(conditions, fmt.Sprintf("lang = $%d", len(args)))
+	}
+
+	if params.PartialContent != "" {
+		args = append(args, "%"+likeEscaper.Replace(params.PartialContent)+"%", params.PartialContent)
+		likeIdx, textIdx := len(args)-1, len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(content ILIKE $%d OR content %% $%d OR to_tsvector('simple', content) @@ plainto_tsquery('simple', $%d))",
+			likeIdx, textIdx, textIdx,
+		))
+	}
+
+	if len(params.Tags) > 0 {
+		var condition string
+		condition, args = tagsCondition(params.Tags, args)
+		conditions = append(conditions, condition)
+	}
+
+	query := "SELECT * FROM locale.localeitems_list WHERE " + strings.Join(conditions, " AND ")
+
+	direction := "DESC"
+	if params.SortAsc {
+		direction = "ASC"
+	}
+
+	args = append(args, params.PageSize, (params.Page-1)*params.PageSize)
+	query += fmt.Sprintf(" ORDER BY updated_at %s, aggregate_id, lang LIMIT $%d OFFSET $%d", direction, len(args)-1, len(args))
+
 	result := make([]LocaleItemList, 0)
-	err := state.repository.Select(&result, "SELECT * FROM locale.localeitems_list WHERE context = $1", context)
+	err := state.repository.Select(&result, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,112 @@
+package aggregate
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pix303/postgres-util-go/pkg/postgres"
+)
+
+// SnapshotSchemaVersion is the shape version of serialized LocaleItemAggregate:
+// increase it when the aggregate struct changes so that old snapshots are invalidated
+const SnapshotSchemaVersion = 6
+
+// SnapshotEvery is the number of events after which a new snapshot is stored
+const SnapshotEvery = 50
+
+// LocaleItemAggregateSnapshotRepository persists serialized aggregates with their version
+type LocaleItemAggregateSnapshotRepository struct {
+	repository *sqlx.DB
+}
+
+func NewLocaleItemAggregateSnapshotRepository() (*LocaleItemAggregateSnapshotRepository, error) {
+	db, err := postgres.NewPostgresqlRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &LocaleItemAggregateSnapshotRepository{repository: db}, nil
+}
+
+type localeItemSnapshot struct {
+	AggregateID   string    `db:"aggregate_id"`
+	Version       int       `db:"version"`
+	SchemaVersion int       `db:"schema_version"`
+	CreatedAt     time.Time `db:"created_at"`
+	Data          string    `db:"data"`
+}
+
+var snapshotInsertOrUpdate string = `INSERT INTO locale.localeitem_snapshot (aggregate_id, version, schema_version, created_at, data)
+VALUES (:aggregate_id, :version, :schema_version, :created_at, :data)
+ON CONFLICT (aggregate_id)
+DO UPDATE SET
+    version = :version,
+    schema_version = :schema_version,
+    created_at = :created_at,
+    data = :data;
+`
+
+// Save stores the aggregate as latest snapshot
+func (snapshots *LocaleItemAggregateSnapshotRepository) Save(aggregate LocaleItemAggregate) error {
+	datajson, err := json.Marshal(aggregate)
+	if err != nil {
+		return err
+	}
+
+	_, err = snapshots.repository.NamedExec(snapshotInsertOrUpdate, localeItemSnapshot{
+		AggregateID:   aggregate.AggregateID,
+		Version:       aggregate.Version,
+		SchemaVersion: SnapshotSchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Data:          string(datajson),
+	})
+	return err
+}
+
+const selectSnapshotByID = `SELECT * FROM locale.localeitem_snapshot WHERE aggregate_id = $1`
+
+// Load returns the latest snapshot of aggregate; found is false if there is none or it has an old schema version
+func (snapshots *LocaleItemAggregateSnapshotRepository) Load(aggregateID string) (aggregate LocaleItemAggregate, found bool, err error) {
+	aggregate = NewLocaleItemAggregate()
+
+	snapshot := localeItemSnapshot{}
+	err = snapshots.repository.Get(&snapshot, selectSnapshotByID, aggregateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return aggregate, false, nil
+	}
+	if err != nil {
+		return aggregate, false, err
+	}
+
+	if snapshot.SchemaVersion != SnapshotSchemaVersion {
+		return aggregate, false, snapshots.Invalidate(aggregateID)
+	}
+
+	err = json.Unmarshal([]byte(snapshot.Data), &aggregate)
+	if err != nil {
+		return NewLocaleItemAggregate(), false, err
+	}
+	return aggregate, true, nil
+}
+
+const deleteSnapshotByID = `DELETE FROM locale.localeitem_snapshot WHERE aggregate_id = $1`
+
+// Invalidate removes the snapshot of aggregate
+func (snapshots *LocaleItemAggregateSnapshotRepository) Invalidate(aggregateID string) error {
+	_, err := snapshots.repository.Exec(deleteSnapshotByID, aggregateID)
+	return err
+}
+
+const deleteSnapshotsBySchemaVersion = `DELETE FROM locale.localeitem_snapshot WHERE schema_version <> $1`
+
+// InvalidateOutdated removes all snapshots with a schema version different from the current one
+func (snapshots *LocaleItemAggregateSnapshotRepository) InvalidateOutdated() error {
+	_, err := snapshots.repository.Exec(deleteSnapshotsBySchemaVersion, SnapshotSchemaVersion)
+	return err
+}
+
+func (snapshots *LocaleItemAggregateSnapshotRepository) Close() error {
+	return snapshots.repository.Close()
+}
@@ -0,0 +1,440 @@
+package aggregate
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/pix303/cinecity/pkg/actor"
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/eventstore-go-v2/pkg/store"
+	"github.com/pix303/localemgmt-go/domain/pkg/contexts"
+	"github.com/pix303/localemgmt-go/domain/pkg/lang"
+	domain "github.com/pix303/localemgmt-go/domain/pkg/localeitem/events"
+)
+
+var (
+	ErrStaleVersion = errors.New("aggregate version differs from the expected one")
+	ErrStoreEvent   = errors.New("event store did not store the event")
+	ErrKeyConflict  = errors.New("key already used in context")
+	ErrBatchAborted = errors.New("not appended because another event of the batch was rejected")
+)
+
+// LocaleItemWriterState is the single writer of locale item events: it appends them one at a time
+// through the event store actor, so that subscribers are notified, checking the expected version of
+// their aggregate and the uniqueness of keys in context just before; no other write can happen
+// between the checks and the append.
+// It does not subscribe the event store, so waiting for its replies never blocks store notifies
+type LocaleItemWriterState struct {
+	events *LocaleItemEventRepository
+	keys   *LocaleItemKeyRepository
+}
+
+var LocaleItemWriterAddress = actor.NewAddress("local", "localeitem-writer")
+
+func NewLocaleItemWriterState() (*LocaleItemWriterState, error) {
+	repo, err := NewLocaleItemEventRepository()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := NewLocaleItemKeyRepository()
+	if err != nil {
+		return nil, err
+	}
+	return &LocaleItemWriterState{events: repo, keys: keys}, nil
+}
+
+// AppendLocaleItemEvent is an event to append with the version its aggregate must be at;
+// 0 skips the check
+type AppendLocaleItemEvent struct {
+	Event           events.StoreEvent
+	ExpectedVersion int
+}
+
+// AppendLocaleItemEventsBody is the command message to append locale item events in order; each
+// event is appended or rejected on its own, the event store has no multi event transaction
+type AppendLocaleItemEventsBody struct {
+	Events []AppendLocaleItemEvent
+}
+
+// AppendLocaleItemEventsBodyResult has, at the index of each event, nil if appended or the error
+// rejecting it: ErrStaleVersion if its aggregate moved from the expected version, ErrKeyConflict if
+// another item has its key in context
+type AppendLocaleItemEventsBodyResult struct {
+	Errors []error
+}
+
+// AppendLocaleItemEventsBatchBody is the command message to append locale item events all or none:
+// expected versions and keys of all events are checked first, then they are stored in one transaction
+// and projections are updated once for the batch. The result has at the index of each event its
+// error, ErrBatchAborted for the accepted ones if another was rejected
+type AppendLocaleItemEventsBatchBody struct {
+	Events []AppendLocaleItemEvent
+}
+
+// VerifyMoveContextItemsBody is the query message to check that the items in the subtree of context
+// From can be moved to the same path under To; contexts.ErrInvalidName if a context would be too long
+type VerifyMoveContextItemsBody struct {
+	From string
+	To   string
+}
+
+type VerifyMoveContextItemsBodyResult struct{}
+
+// MoveContextItemsBody is the command message to move the items in the subtree of context From to the
+// same path under To; moved items are no more in the subtree of From, so repeating it resumes a move
+// that failed on some of them
+type MoveContextItemsBody struct {
+	From   string
+	To     string
+	UserID string
+}
+
+// MoveContextItemsBodyResult has the number of items moved and of the ones that could not be moved
+type MoveContextItemsBodyResult struct {
+	Moved  int
+	Failed int
+}
+
+// CanonicalizeLangsBody is the command message to rename the langs of items stored before langs were
+// canonicalized, as en_US or EN, to their canonical code; renamed langs are no more found, so
+// repeating it resumes a run that failed on some items
+type CanonicalizeLangsBody struct {
+	UserID string
+}
+
+// CanonicalizeLangsBodyResult has the number of langs renamed and of the ones that could not be
+type CanonicalizeLangsBodyResult struct {
+	Canonicalized int
+	Failed        int
+}
+
+func (state *LocaleItemWriterState) Process(msg actor.Message) {
+	switch payload := msg.Body.(type) {
+	case AppendLocaleItemEventsBody:
+		errs := state.appendEvents(payload.Events)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(AppendLocaleItemEventsBodyResult{Errors: errs}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, nil)
+		}
+
+	case AppendLocaleItemEventsBatchBody:
+		errs, err := state.appendBatch(payload.Events)
+		if err != nil {
+			slog.Error(ErrToAppendAggregateEvents, slog.String("error", err.Error()))
+		}
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(AppendLocaleItemEventsBodyResult{Errors: errs}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case VerifyMoveContextItemsBody:
+		_, err := state.newMoveContextEvents(payload.From, payload.To, "")
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(VerifyMoveContextItemsBodyResult{}, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case MoveContextItemsBody:
+		result, err := state.moveContextItems(payload)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(result, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+
+	case CanonicalizeLangsBody:
+		result, err := state.canonicalizeLangs(payload.UserID)
+		if msg.WithReturn {
+			returnMsg := actor.NewReturnMessage(result, msg)
+			msg.ReturnChan <- actor.NewWrappedMessage(&returnMsg, err)
+		}
+	}
+}
+
+// appendEvents appends each event whose aggregate is at the expected version; versions read are
+// kept for the next events of the same aggregate
+func (state *LocaleItemWriterState) appendEvents(evts []AppendLocaleItemEvent) []error {
+	errs := make([]error, len(evts))
+	versions := make(map[string]int)
+	for i, e := range evts {
+		aggregateID := e.Event.AggregateID
+		version, known := versions[aggregateID]
+		if e.ExpectedVersion > 0 && !known {
+			v, err := state.events.Version(aggregateID)
+			if err != nil {
+				slog.Error(ErrToRetriveAggregateEvents, slog.String("aggregateId", aggregateID), slog.String("error", err.Error()))
+				errs[i] = err
+				continue
+			}
+			version, known = v, true
+			versions[aggregateID] = version
+		}
+		if e.ExpectedVersion > 0 && version != e.ExpectedVersion {
+			errs[i] = ErrStaleVersion
+			continue
+		}
+
+		err := state.appendEvent(e.Event)
+		if errors.Is(err, ErrKeyConflict) {
+			errs[i] = err
+			continue
+		}
+		if err != nil {
+			slog.Error(ErrToAppendAggregateEvents, slog.String("aggregateId", aggregateID), slog.String("error", err.Error()))
+			errs[i] = err
+			// event may be stored anyway: read the version again if needed
+			delete(versions, aggregateID)
+			continue
+		}
+		if known {
+			versions[aggregateID] = version + 1
+		}
+	}
+	return errs
+}
+
+// appendBatch checks and stores evts in one transaction, with their key changes; no other append can
+// happen meanwhile, since the writer is the only one appending locale item events. Stored events are
+// not notified by the event store: the aggregate actor is sent the ids of their aggregates instead
+func (state *LocaleItemWriterState) appendBatch(evts []AppendLocaleItemEvent) (errs []error, err error) {
+	errs = make([]error, len(evts))
+	tx, err := state.events.Begin()
+	if err != nil {
+		return errs, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if rberr := tx.Rollback(); rberr != nil {
+				slog.Error("transation fail so apply rollback", slog.String("error", rberr.Error()))
+			}
+		}
+	}()
+
+	aggregateIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, e := range evts {
+		if !seen[e.Event.AggregateID] {
+			seen[e.Event.AggregateID] = true
+			aggregateIDs = append(aggregateIDs, e.Event.AggregateID)
+		}
+	}
+	versions, err := state.events.Versions(tx, aggregateIDs)
+	if err != nil {
+		return errs, err
+	}
+
+	rejected := false
+	stored := make([]events.StoreEvent, 0, len(evts))
+	for i, e := range evts {
+		aggregateID := e.Event.AggregateID
+		if e.ExpectedVersion > 0 && versions[aggregateID] != e.ExpectedVersion {
+			errs[i] = ErrStaleVersion
+			rejected = true
+		}
+		versions[aggregateID]++
+
+		if changesKey(e.Event.EventType) {
+			err = state.keys.Apply(tx, e.Event)
+			if errors.Is(err, ErrKeyConflict) {
+				errs[i] = err
+				rejected = true
+			} else if err != nil {
+				return errs, err
+			}
+		}
+		stored = append(stored, e.Event)
+	}
+
+	if rejected {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = ErrBatchAborted
+			}
+		}
+		return errs, nil
+	}
+
+	err = state.events.Append(tx, stored)
+	if err != nil {
+		return errs, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return errs, err
+	}
+	committed = true
+
+	msg := actor.NewMessage(
+		LocaleItemAggregateAddress,
+		LocaleItemWriterAddress,
+		LocaleItemEventsAppendedBody{AggregateIDs: aggregateIDs},
+		false,
+	)
+	err = actor.SendMessage(msg)
+	if err != nil {
+		// events are stored: projections are updated with the next events of the aggregates
+		slog.Error(ErrToPersistAggregate, slog.String("error", err.Error()))
+	}
+	return errs, nil
+}
+
+// appendEvent stores evt through the event store; the key changes of evt are committed only if
+// evt is stored
+func (state *LocaleItemWriterState) appendEvent(evt events.StoreEvent) (err error) {
+	if !changesKey(evt.EventType) {
+		return storeEvent(evt)
+	}
+
+	tx, err := state.keys.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rberr := tx.Rollback(); rberr != nil {
+				slog.Error("transation fail so apply rollback", slog.String("error", rberr.Error()))
+			}
+		}
+	}()
+
+	err = state.keys.Apply(tx, evt)
+	if err != nil {
+		return err
+	}
+	err = storeEvent(evt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// newMoveContextEvents returns the context changed events moving the items in the subtree of context
+// from to the same path under to
+func (state *LocaleItemWriterState) newMoveContextEvents(from string, to string, userID string) ([]events.StoreEvent, error) {
+	items, err := state.keys.RetriveInSubtree(from)
+	if err != nil {
+		return nil, err
+	}
+
+	evts := make([]events.StoreEvent, 0, len(items))
+	for _, item := range items {
+		context := to + strings.TrimPrefix(item.Context, from)
+		if len(context) > contexts.MaxNameLength {
+			return nil, contexts.ErrInvalidName
+		}
+		evt, err := domain.NewChangeContextEvent(item.AggregateID, context, userID)
+		if err != nil {
+			return nil, err
+		}
+		evts = append(evts, evt)
+	}
+	return evts, nil
+}
+
+// moveContextItems appends the events moving the items of the subtree; no item is moved if one of
+// them can not be, otherwise each is moved on its own
+func (state *LocaleItemWriterState) moveContextItems(payload MoveContextItemsBody) (MoveContextItemsBodyResult, error) {
+	result := MoveContextItemsBodyResult{}
+	evts, err := state.newMoveContextEvents(payload.From, payload.To, payload.UserID)
+	if err != nil {
+		return result, err
+	}
+
+	for _, evt := range evts {
+		err = state.appendEvent(evt)
+		if err != nil {
+			slog.Error(ErrToAppendAggregateEvents, slog.String("aggregateId", evt.AggregateID), slog.String("error", err.Error()))
+			result.Failed++
+			continue
+		}
+		result.Moved++
+	}
+	return result, nil
+}
+
+// canonicalizeLangs appends a lang canonicalized event for every lang of an item that is not in its
+// canonical form; langs that are not valid codes are left as they are
+func (state *LocaleItemWriterState) canonicalizeLangs(userID string) (CanonicalizeLangsBodyResult, error) {
+	result := CanonicalizeLangsBodyResult{}
+	langs, err := state.events.RetriveLangs()
+	if err != nil {
+		return result, err
+	}
+
+	for _, l := range langs {
+		canonical, err := lang.Canonicalize(l.Lang)
+		if err != nil {
+			slog.Warn("lang of locale item can not be canonicalized", slog.String("aggregateId", l.AggregateID), slog.String("lang", l.Lang))
+			continue
+		}
+		if canonical == l.Lang {
+			continue
+		}
+
+		evt, err := domain.NewCanonicalizeLangEvent(l.AggregateID, l.Lang, canonical, userID)
+		if err == nil {
+			err = state.appendEvent(evt)
+		}
+		if err != nil {
+			slog.Error(ErrToAppendAggregateEvents, slog.String("aggregateId", l.AggregateID), slog.String("error", err.Error()))
+			result.Failed++
+			continue
+		}
+		result.Canonicalized++
+	}
+	return result, nil
+}
+
+// BackfillCanonicalLangs renames, through the locale item writer, the langs of items stored before
+// langs were canonicalized; it can run at every startup
+func BackfillCanonicalLangs(userID string) error {
+	msg := actor.NewMessage(
+		LocaleItemWriterAddress,
+		nil,
+		CanonicalizeLangsBody{UserID: userID},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[CanonicalizeLangsBodyResult](msg)
+	if err != nil {
+		return err
+	}
+	if result.Canonicalized > 0 || result.Failed > 0 {
+		slog.Info("langs of locale items canonicalized", slog.Int("canonicalized", result.Canonicalized), slog.Int("failed", result.Failed))
+	}
+	return nil
+}
+
+func storeEvent(evt events.StoreEvent) error {
+	msg := actor.NewMessage(
+		store.EventStoreAddress,
+		LocaleItemWriterAddress,
+		store.AddEventBody{Event: evt},
+		true,
+	)
+	result, err := actor.SendMessageWithResponse[store.AddEventBodyResult](msg)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return ErrStoreEvent
+	}
+	return nil
+}
+
+func (state *LocaleItemWriterState) GetState() any {
+	return nil
+}
+
+func (state *LocaleItemWriterState) Shutdown() {
+	err := state.events.Close()
+	if err != nil {
+		slog.Error("fail to close events repository", slog.String("error", err.Error()))
+	}
+	state.events = nil
+	err = state.keys.Close()
+	if err != nil {
+		slog.Error("fail to close keys repository", slog.String("error", err.Error()))
+	}
+	state.keys = nil
+}
@@ -1,7 +1,9 @@
 package events
 
 import (
+	"github.com/google/uuid"
 	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/localemgmt-go/domain/pkg/plural"
 )
 
 const LocaleItemAggregateName = "localeitem"
@@ -9,17 +11,29 @@ const LocaleItemAggregateName = "localeitem"
 const CreateLocaleItemStoreEventType = "created-localeitem"
 
 type CreateLocaleItemPayload struct {
-	events.StoreEvent
-	Content string
-	Context string
-	Lang    string
+	SchemaVersion int
+	Content       string
+	Context       string
+	Lang          string
+	Key           string
+	Plurals       plural.Forms
+	MaxChars      int
+	MaxGraphemes  int
+	Description   string
 }
 
-func NewCreateEvent(content string, context string, lang string, userID string) (events.StoreEvent, error) {
+// NewCreateEvent creates a locale item; maxChars and maxGraphemes limit the length of its translations, 0 is no limit
+func NewCreateEvent(content string, plurals plural.Forms, context string, lang string, key string, maxChars int, maxGraphemes int, description string, userID string) (events.StoreEvent, error) {
 	payload := CreateLocaleItemPayload{
-		Content: content,
-		Context: context,
-		Lang:    lang,
+		SchemaVersion: CurrentSchemaVersion(CreateLocaleItemStoreEventType),
+		Content:       content,
+		Context:       context,
+		Lang:          lang,
+		Key:           key,
+		Plurals:       plurals,
+		MaxChars:      maxChars,
+		MaxGraphemes:  maxGraphemes,
+		Description:   description,
 	}
 
 	evt, err := events.NewStoreEvent(CreateLocaleItemStoreEventType, LocaleItemAggregateName, userID, payload, nil)
@@ -33,16 +47,366 @@ func NewCreateEvent(content string, context string, lang string, userID string)
 const UpdateTranslationStoreEventType = "update-translation"
 
 type UpdateTranslationLocaleItemPayload struct {
-	Content string
-	Lang    string
+	SchemaVersion int
+	Content       string
+	Lang          string
+	Plurals       plural.Forms
 }
 
-func NewUpdateEvent(aggregateID string, content string, lang string, userID string) (events.StoreEvent, error) {
+func NewUpdateEvent(aggregateID string, content string, plurals plural.Forms, lang string, userID string) (events.StoreEvent, error) {
 	payload := UpdateTranslationLocaleItemPayload{
+		CurrentSchemaVersion(UpdateTranslationStoreEventType),
 		content,
 		lang,
+		plurals,
 	}
 
 	evt, err := events.NewStoreEvent(UpdateTranslationStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
 	return evt, err
 }
+
+const ArchiveLocaleItemStoreEventType = "archived-localeitem"
+
+type ArchiveLocaleItemPayload struct {
+	SchemaVersion int
+}
+
+func NewArchiveEvent(aggregateID string, userID string) (events.StoreEvent, error) {
+	payload := ArchiveLocaleItemPayload{
+		CurrentSchemaVersion(ArchiveLocaleItemStoreEventType),
+	}
+
+	evt, err := events.NewStoreEvent(ArchiveLocaleItemStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const RestoreLocaleItemStoreEventType = "restored-localeitem"
+
+type RestoreLocaleItemPayload struct {
+	SchemaVersion int
+}
+
+func NewRestoreEvent(aggregateID string, userID string) (events.StoreEvent, error) {
+	payload := RestoreLocaleItemPayload{
+		CurrentSchemaVersion(RestoreLocaleItemStoreEventType),
+	}
+
+	evt, err := events.NewStoreEvent(RestoreLocaleItemStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const DeleteLocaleItemStoreEventType = "deleted-localeitem"
+
+type DeleteLocaleItemPayload struct {
+	SchemaVersion int
+}
+
+func NewDeleteEvent(aggregateID string, userID string) (events.StoreEvent, error) {
+	payload := DeleteLocaleItemPayload{
+		CurrentSchemaVersion(DeleteLocaleItemStoreEventType),
+	}
+
+	evt, err := events.NewStoreEvent(DeleteLocaleItemStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const RemoveTranslationStoreEventType = "translation-removed"
+
+type RemoveTranslationLocaleItemPayload struct {
+	SchemaVersion int
+	Lang          string
+}
+
+func NewRemoveTranslationEvent(aggregateID string, lang string, userID string) (events.StoreEvent, error) {
+	payload := RemoveTranslationLocaleItemPayload{
+		CurrentSchemaVersion(RemoveTranslationStoreEventType),
+		lang,
+	}
+
+	evt, err := events.NewStoreEvent(RemoveTranslationStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const ChangeContextStoreEventType = "context-changed"
+
+type ChangeContextLocaleItemPayload struct {
+	SchemaVersion int
+	Context       string
+}
+
+func NewChangeContextEvent(aggregateID string, context string, userID string) (events.StoreEvent, error) {
+	payload := ChangeContextLocaleItemPayload{
+		CurrentSchemaVersion(ChangeContextStoreEventType),
+		context,
+	}
+
+	evt, err := events.NewStoreEvent(ChangeContextStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const ChangeReferenceLangStoreEventType = "reference-lang-changed"
+
+type ChangeReferenceLangLocaleItemPayload struct {
+	SchemaVersion int
+	Lang          string
+}
+
+func NewChangeReferenceLangEvent(aggregateID string, lang string, userID string) (events.StoreEvent, error) {
+	payload := ChangeReferenceLangLocaleItemPayload{
+		CurrentSchemaVersion(ChangeReferenceLangStoreEventType),
+		lang,
+	}
+
+	evt, err := events.NewStoreEvent(ChangeReferenceLangStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const CanonicalizeLangStoreEventType = "lang-canonicalized"
+
+// CanonicalizeLangLocaleItemPayload renames Lang, stored before langs were canonicalized, to its
+// canonical code in translations and comments
+type CanonicalizeLangLocaleItemPayload struct {
+	SchemaVersion int
+	Lang          string
+	CanonicalLang string
+}
+
+func NewCanonicalizeLangEvent(aggregateID string, lang string, canonicalLang string, userID string) (events.StoreEvent, error) {
+	payload := CanonicalizeLangLocaleItemPayload{
+		CurrentSchemaVersion(CanonicalizeLangStoreEventType),
+		lang,
+		canonicalLang,
+	}
+
+	evt, err := events.NewStoreEvent(CanonicalizeLangStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const ChangeLengthLimitStoreEventType = "length-limit-changed"
+
+type ChangeLengthLimitLocaleItemPayload struct {
+	SchemaVersion int
+	MaxChars      int
+	MaxGraphemes  int
+}
+
+// NewChangeLengthLimitEvent sets the max length of item translations; 0 is no limit
+func NewChangeLengthLimitEvent(aggregateID string, maxChars int, maxGraphemes int, userID string) (events.StoreEvent, error) {
+	payload := ChangeLengthLimitLocaleItemPayload{
+		CurrentSchemaVersion(ChangeLengthLimitStoreEventType),
+		maxChars,
+		maxGraphemes,
+	}
+
+	evt, err := events.NewStoreEvent(ChangeLengthLimitStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const AddTagStoreEventType = "tag-added"
+const RemoveTagStoreEventType = "tag-removed"
+
+type TagLocaleItemPayload struct {
+	SchemaVersion int
+	Tag           string
+}
+
+func NewAddTagEvent(aggregateID string, tag string, userID string) (events.StoreEvent, error) {
+	return newTagEvent(AddTagStoreEventType, aggregateID, tag, userID)
+}
+
+func NewRemoveTagEvent(aggregateID string, tag string, userID string) (events.StoreEvent, error) {
+	return newTagEvent(RemoveTagStoreEventType, aggregateID, tag, userID)
+}
+
+func newTagEvent(eventType string, aggregateID string, tag string, userID string) (events.StoreEvent, error) {
+	payload := TagLocaleItemPayload{
+		CurrentSchemaVersion(eventType),
+		tag,
+	}
+
+	evt, err := events.NewStoreEvent(eventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const (
+	DraftTranslationStatus       = "draft"
+	NeedsReviewTranslationStatus = "needs-review"
+	ApprovedTranslationStatus    = "approved"
+	RejectedTranslationStatus    = "rejected"
+)
+
+const RequestReviewTranslationStoreEventType = "translation-review-requested"
+const ApproveTranslationStoreEventType = "translation-approved"
+const RejectTranslationStoreEventType = "translation-rejected"
+
+type TranslationStatusLocaleItemPayload struct {
+	SchemaVersion int
+	Lang          string
+}
+
+func NewRequestReviewEvent(aggregateID string, lang string, userID string) (events.StoreEvent, error) {
+	return newTranslationStatusEvent(RequestReviewTranslationStoreEventType, aggregateID, lang, userID)
+}
+
+func NewApproveEvent(aggregateID string, lang string, userID string) (events.StoreEvent, error) {
+	return newTranslationStatusEvent(ApproveTranslationStoreEventType, aggregateID, lang, userID)
+}
+
+func NewRejectEvent(aggregateID string, lang string, userID string) (events.StoreEvent, error) {
+	return newTranslationStatusEvent(RejectTranslationStoreEventType, aggregateID, lang, userID)
+}
+
+func newTranslationStatusEvent(eventType string, aggregateID string, lang string, userID string) (events.StoreEvent, error) {
+	payload := TranslationStatusLocaleItemPayload{
+		CurrentSchemaVersion(eventType),
+		lang,
+	}
+
+	evt, err := events.NewStoreEvent(eventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const RevertTranslationStoreEventType = "translation-reverted"
+
+// RevertTranslationLocaleItemPayload is an update with the content the translation had at RevertedToVersion
+type RevertTranslationLocaleItemPayload struct {
+	UpdateTranslationLocaleItemPayload
+	RevertedToVersion int
+}
+
+func NewRevertEvent(aggregateID string, content string, plurals plural.Forms, lang string, revertedToVersion int, userID string) (events.StoreEvent, error) {
+	payload := RevertTranslationLocaleItemPayload{
+		UpdateTranslationLocaleItemPayload{
+			CurrentSchemaVersion(RevertTranslationStoreEventType),
+			content,
+			lang,
+			plurals,
+		},
+		revertedToVersion,
+	}
+
+	evt, err := events.NewStoreEvent(RevertTranslationStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const AddCommentStoreEventType = "comment-added"
+
+type AddCommentLocaleItemPayload struct {
+	SchemaVersion int
+	CommentId     string
+	ParentId      string
+	Lang          string
+	Content       string
+}
+
+// NewAddCommentEvent creates a comment with a new id; parentID is the replied comment and lang scopes the comment to a translation, both optional
+func NewAddCommentEvent(aggregateID string, parentID string, lang string, content string, userID string) (events.StoreEvent, error) {
+	payload := AddCommentLocaleItemPayload{
+		CurrentSchemaVersion(AddCommentStoreEventType),
+		uuid.NewString(),
+		parentID,
+		lang,
+		content,
+	}
+
+	evt, err := events.NewStoreEvent(AddCommentStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const EditCommentStoreEventType = "comment-edited"
+
+type EditCommentLocaleItemPayload struct {
+	SchemaVersion int
+	CommentId     string
+	Content       string
+}
+
+func NewEditCommentEvent(aggregateID string, commentID string, content string, userID string) (events.StoreEvent, error) {
+	payload := EditCommentLocaleItemPayload{
+		CurrentSchemaVersion(EditCommentStoreEventType),
+		commentID,
+		content,
+	}
+
+	evt, err := events.NewStoreEvent(EditCommentStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const ResolveCommentStoreEventType = "comment-resolved"
+
+type ResolveCommentLocaleItemPayload struct {
+	SchemaVersion int
+	CommentId     string
+}
+
+func NewResolveCommentEvent(aggregateID string, commentID string, userID string) (events.StoreEvent, error) {
+	payload := ResolveCommentLocaleItemPayload{
+		CurrentSchemaVersion(ResolveCommentStoreEventType),
+		commentID,
+	}
+
+	evt, err := events.NewStoreEvent(ResolveCommentStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const ChangeDescriptionStoreEventType = "description-changed"
+
+type ChangeDescriptionLocaleItemPayload struct {
+	SchemaVersion int
+	Description   string
+}
+
+func NewChangeDescriptionEvent(aggregateID string, description string, userID string) (events.StoreEvent, error) {
+	payload := ChangeDescriptionLocaleItemPayload{
+		CurrentSchemaVersion(ChangeDescriptionStoreEventType),
+		description,
+	}
+
+	evt, err := events.NewStoreEvent(ChangeDescriptionStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const AddAttachmentStoreEventType = "attachment-added"
+
+type AddAttachmentLocaleItemPayload struct {
+	SchemaVersion int
+	AttachmentId  string
+	FileName      string
+	ContentType   string
+	Size          int64
+}
+
+// NewAttachmentID returns the id of a new attachment, to be used as its blob name before adding it
+func NewAttachmentID() string {
+	return uuid.NewString()
+}
+
+func NewAddAttachmentEvent(aggregateID string, attachmentID string, fileName string, contentType string, size int64, userID string) (events.StoreEvent, error) {
+	payload := AddAttachmentLocaleItemPayload{
+		CurrentSchemaVersion(AddAttachmentStoreEventType),
+		attachmentID,
+		fileName,
+		contentType,
+		size,
+	}
+
+	evt, err := events.NewStoreEvent(AddAttachmentStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
+
+const RemoveAttachmentStoreEventType = "attachment-removed"
+
+type RemoveAttachmentLocaleItemPayload struct {
+	SchemaVersion int
+	AttachmentId  string
+}
+
+func NewRemoveAttachmentEvent(aggregateID string, attachmentID string, userID string) (events.StoreEvent, error) {
+	payload := RemoveAttachmentLocaleItemPayload{
+		CurrentSchemaVersion(RemoveAttachmentStoreEventType),
+		attachmentID,
+	}
+
+	evt, err := events.NewStoreEvent(RemoveAttachmentStoreEventType, LocaleItemAggregateName, userID, payload, &aggregateID)
+	return evt, err
+}
@@ -0,0 +1,175 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/eventstore-go-v2/pkg/utils"
+	"github.com/pix303/localemgmt-go/domain/pkg/plural"
+)
+
+var (
+	ErrDecodePayload        = errors.New("error on decode payload")
+	ErrMissingUpcaster      = errors.New("missing upcaster for payload schema version")
+	ErrUnknownSchemaVersion = errors.New("payload schema version newer than current")
+	ErrUpcastPayloadType    = errors.New("unexpected payload type for upcaster")
+)
+
+// LegacySchemaVersion is the schema version of payloads stored before versioning
+const LegacySchemaVersion = 1
+
+// currentSchemaVersions are the payload schema versions written by constructors;
+// event types not listed are at LegacySchemaVersion
+var currentSchemaVersions = map[string]int{
+	CreateLocaleItemStoreEventType: 4,
+}
+
+// CurrentSchemaVersion returns the payload schema version of new events of eventType
+func CurrentSchemaVersion(eventType string) int {
+	version, ok := currentSchemaVersions[eventType]
+	if !ok {
+		return LegacySchemaVersion
+	}
+	return version
+}
+
+type schemaKey struct {
+	eventType string
+	version   int
+}
+
+// upcaster decodes a payload stored at a schema version in its concrete type and migrates it to the next one
+type upcaster struct {
+	decode func(data string) (any, error)
+	upcast func(payload any) (any, error)
+}
+
+var upcasters = map[schemaKey]upcaster{}
+
+// RegisterUpcaster adds the upcaster migrating payloads of eventType from fromVersion, stored as From,
+// to fromVersion+1, decoded as To
+func RegisterUpcaster[From any, To any](eventType string, fromVersion int, upcast func(From) To) {
+	upcasters[schemaKey{eventType, fromVersion}] = upcaster{
+		decode: func(data string) (any, error) {
+			payload, err := utils.DecodePayload[From](data)
+			if err != nil {
+				return nil, err
+			}
+			return *payload, nil
+		},
+		upcast: func(payload any) (any, error) {
+			from, ok := payload.(From)
+			if !ok {
+				return nil, fmt.Errorf("%w: %T", ErrUpcastPayloadType, payload)
+			}
+			return upcast(from), nil
+		},
+	}
+}
+
+// createLocaleItemPayloadV1 is the create payload stored before versioning; the store event it embedded
+// is ignored on decoding
+type createLocaleItemPayloadV1 struct {
+	Content string
+	Context string
+	Lang    string
+	Key     string
+	Plurals plural.Forms
+}
+
+type createLocaleItemPayloadV2 struct {
+	SchemaVersion int
+	Content       string
+	Context       string
+	Lang          string
+	Key           string
+	Plurals       plural.Forms
+}
+
+type createLocaleItemPayloadV3 struct {
+	SchemaVersion int
+	Content       string
+	Context       string
+	Lang          string
+	Key           string
+	Plurals       plural.Forms
+	MaxChars      int
+	MaxGraphemes  int
+}
+
+func init() {
+	// v2 create payload has its own fields only
+	RegisterUpcaster(CreateLocaleItemStoreEventType, 1, func(p createLocaleItemPayloadV1) createLocaleItemPayloadV2 {
+		return createLocaleItemPayloadV2{2, p.Content, p.Context, p.Lang, p.Key, p.Plurals}
+	})
+	// v3 create payload has length limits: old items have none
+	RegisterUpcaster(CreateLocaleItemStoreEventType, 2, func(p createLocaleItemPayloadV2) createLocaleItemPayloadV3 {
+		return createLocaleItemPayloadV3{3, p.Content, p.Context, p.Lang, p.Key, p.Plurals, 0, 0}
+	})
+	// v4 create payload has a description
+	RegisterUpcaster(CreateLocaleItemStoreEventType, 3, func(p createLocaleItemPayloadV3) CreateLocaleItemPayload {
+		return CreateLocaleItemPayload{4, p.Content, p.Context, p.Lang, p.Key, p.Plurals, p.MaxChars, p.MaxGraphemes, ""}
+	})
+}
+
+// DecodePayload decodes the event payload in T, upcasting it from the concrete type of its schema version
+// if older than the current one of its type
+func DecodePayload[T any](evt events.StoreEvent) (T, error) {
+	var result T
+
+	// gob matches fields by name, so every version decodes in T enough to read its schema version
+	payload, err := utils.DecodePayload[T](evt.PayloadData)
+	if err != nil {
+		return result, fmt.Errorf("%w %s: %w", ErrDecodePayload, evt.EventType, err)
+	}
+	result = *payload
+
+	version := schemaVersion(result)
+	current := CurrentSchemaVersion(evt.EventType)
+	if version > current {
+		return result, fmt.Errorf("%w: %s v%d", ErrUnknownSchemaVersion, evt.EventType, version)
+	}
+	if version == current {
+		return result, nil
+	}
+
+	first, ok := upcasters[schemaKey{evt.EventType, version}]
+	if !ok {
+		return result, fmt.Errorf("%w: %s v%d", ErrMissingUpcaster, evt.EventType, version)
+	}
+	upcasted, err := first.decode(evt.PayloadData)
+	if err != nil {
+		return result, fmt.Errorf("%w %s v%d: %w", ErrDecodePayload, evt.EventType, version, err)
+	}
+	for ; version < current; version++ {
+		u, ok := upcasters[schemaKey{evt.EventType, version}]
+		if !ok {
+			return result, fmt.Errorf("%w: %s v%d", ErrMissingUpcaster, evt.EventType, version)
+		}
+		upcasted, err = u.upcast(upcasted)
+		if err != nil {
+			return result, fmt.Errorf("%w %s v%d: %w", ErrDecodePayload, evt.EventType, version, err)
+		}
+	}
+
+	result, ok = upcasted.(T)
+	if !ok {
+		return result, fmt.Errorf("%w %s: %w: %T", ErrDecodePayload, evt.EventType, ErrUpcastPayloadType, upcasted)
+	}
+	return result, nil
+}
+
+// schemaVersion returns the SchemaVersion field of payload, LegacySchemaVersion if not set
+func schemaVersion(payload any) int {
+	v := reflect.ValueOf(payload)
+	if v.Kind() != reflect.Struct {
+		return LegacySchemaVersion
+	}
+	field := v.FieldByName("SchemaVersion")
+	if !field.IsValid() || field.Kind() != reflect.Int || field.Int() <= 0 {
+		return LegacySchemaVersion
+	}
+	return int(field.Int())
+}
@@ -0,0 +1,185 @@
+package events
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pix303/eventstore-go-v2/pkg/events"
+	"github.com/pix303/eventstore-go-v2/pkg/utils"
+	"github.com/pix303/localemgmt-go/domain/pkg/plural"
+)
+
+// legacyCreateLocaleItemPayload is the create payload as stored before versioning
+type legacyCreateLocaleItemPayload struct {
+	events.StoreEvent
+	Content string
+	Context string
+	Lang    string
+	Key     string
+	Plurals plural.Forms
+}
+
+// baselineCreateLocaleItemPayload is the first create payload, before keys and plurals
+type baselineCreateLocaleItemPayload struct {
+	events.StoreEvent
+	Content string
+	Context string
+	Lang    string
+}
+
+func newTestEvent(t *testing.T, eventType string, payload any) events.StoreEvent {
+	t.Helper()
+	data, err := utils.EncodePayload(payload)
+	if err != nil {
+		t.Fatalf("error on encode payload: %v", err)
+	}
+	return events.StoreEvent{EventType: eventType, PayloadData: data}
+}
+
+func TestDecodeCreatePayload(t *testing.T) {
+	plurals := plural.Forms{"one": "# item", "other": "# items"}
+
+	tests := []struct {
+		name    string
+		payload any
+		want    CreateLocaleItemPayload
+	}{
+		{
+			name:    "baseline legacy",
+			payload: baselineCreateLocaleItemPayload{Content: "hello", Context: "home", Lang: "en"},
+			want:    CreateLocaleItemPayload{SchemaVersion: 4, Content: "hello", Context: "home", Lang: "en"},
+		},
+		{
+			name:    "legacy with embedded store event",
+			payload: legacyCreateLocaleItemPayload{StoreEvent: events.StoreEvent{Id: 7, EventType: "ignored"}, Content: "hello", Context: "home", Lang: "en", Key: "home.title", Plurals: plurals},
+			want:    CreateLocaleItemPayload{SchemaVersion: 4, Content: "hello", Context: "home", Lang: "en", Key: "home.title", Plurals: plurals},
+		},
+		{
+			name:    "v2",
+			payload: createLocaleItemPayloadV2{2, "hello", "home", "en", "home.title", nil},
+			want:    CreateLocaleItemPayload{SchemaVersion: 4, Content: "hello", Context: "home", Lang: "en", Key: "home.title"},
+		},
+		{
+			name:    "v3",
+			payload: createLocaleItemPayloadV3{3, "hello", "home", "en", "", nil, 20, 10},
+			want:    CreateLocaleItemPayload{SchemaVersion: 4, Content: "hello", Context: "home", Lang: "en", MaxChars: 20, MaxGraphemes: 10},
+		},
+		{
+			name:    "current",
+			payload: CreateLocaleItemPayload{4, "hello", "home", "en", "home.title", plurals, 20, 10, "title of home"},
+			want:    CreateLocaleItemPayload{4, "hello", "home", "en", "home.title", plurals, 20, 10, "title of home"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := newTestEvent(t, CreateLocaleItemStoreEventType, tt.payload)
+			got, err := DecodePayload[CreateLocaleItemPayload](evt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCreateEvent(t *testing.T) {
+	evt, err := NewCreateEvent("hello", nil, "home", "en", "home.title", 20, 0, "title of home", "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := DecodePayload[CreateLocaleItemPayload](evt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := CreateLocaleItemPayload{4, "hello", "home", "en", "home.title", nil, 20, 0, "title of home"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeLegacyPayload(t *testing.T) {
+	// payloads of types without upcasters are decoded as they are
+	evt := newTestEvent(t, RemoveTranslationStoreEventType, struct{ Lang string }{"it"})
+	got, err := DecodePayload[RemoveTranslationLocaleItemPayload](evt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Lang != "it" {
+		t.Errorf("got lang %q, want it", got.Lang)
+	}
+
+	evt = newTestEvent(t, ArchiveLocaleItemStoreEventType, struct{}{})
+	_, err = DecodePayload[ArchiveLocaleItemPayload](evt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodePayloadErrors(t *testing.T) {
+	const eventType = "test-upcast"
+	currentSchemaVersions[eventType] = 3
+	t.Cleanup(func() { delete(currentSchemaVersions, eventType) })
+
+	type payload struct {
+		SchemaVersion int
+		Content       string
+	}
+
+	tests := []struct {
+		name string
+		evt  events.StoreEvent
+		want error
+	}{
+		{
+			name: "malformed data",
+			evt:  events.StoreEvent{EventType: CreateLocaleItemStoreEventType, PayloadData: "not base64!"},
+			want: ErrDecodePayload,
+		},
+		{
+			name: "no common fields",
+			evt:  newTestEvent(t, CreateLocaleItemStoreEventType, struct{ Other string }{"x"}),
+			want: ErrDecodePayload,
+		},
+		{
+			name: "newer version",
+			evt:  newTestEvent(t, CreateLocaleItemStoreEventType, CreateLocaleItemPayload{SchemaVersion: 5, Content: "hello"}),
+			want: ErrUnknownSchemaVersion,
+		},
+		{
+			name: "missing upcaster",
+			evt:  newTestEvent(t, eventType, payload{1, "hello"}),
+			want: ErrMissingUpcaster,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodePayload[CreateLocaleItemPayload](tt.evt)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePayloadUpcasterType(t *testing.T) {
+	const eventType = "test-upcast-type"
+	currentSchemaVersions[eventType] = 2
+	key := schemaKey{eventType, 1}
+	RegisterUpcaster(eventType, 1, func(p struct{ Content string }) struct{ Content string } { return p })
+	t.Cleanup(func() {
+		delete(currentSchemaVersions, eventType)
+		delete(upcasters, key)
+	})
+
+	evt := newTestEvent(t, eventType, struct{ Content string }{"hello"})
+	_, err := DecodePayload[CreateLocaleItemPayload](evt)
+	if !errors.Is(err, ErrUpcastPayloadType) {
+		t.Errorf("got error %v, want %v", err, ErrUpcastPayloadType)
+	}
+}
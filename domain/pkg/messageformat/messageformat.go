@@ -0,0 +1,370 @@
+package messageformat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// argument types of ICU MessageFormat; NoneArgType is for {name} without type
+const (
+	NoneArgType          = "none"
+	NumberArgType        = "number"
+	DateArgType          = "date"
+	TimeArgType          = "time"
+	SpelloutArgType      = "spellout"
+	OrdinalArgType       = "ordinal"
+	DurationArgType      = "duration"
+	PluralArgType        = "plural"
+	SelectOrdinalArgType = "selectordinal"
+	SelectArgType        = "select"
+)
+
+var pluralSelectors = []string{"zero", "one", "two", "few", "many", "other"}
+
+// SyntaxError reports an invalid pattern; Offset is the rune position of the error in the content
+type SyntaxError struct {
+	Offset  int
+	Message string
+}
+
+func (err SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", err.Message, err.Offset)
+}
+
+type parser struct {
+	src  []rune
+	pos  int
+	args map[string]string
+}
+
+// Parse validates content as ICU MessageFormat and returns its arguments by name with their type
+func Parse(content string) (map[string]string, error) {
+	p := parser{src: []rune(content), args: make(map[string]string)}
+	err := p.message(false)
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected '}'")
+	}
+	return p.args, nil
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() rune {
+	return p.src[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...any) SyntaxError {
+	return SyntaxError{Offset: p.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// token reads a name, a type or a selector
+func (p *parser) token() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if unicode.IsSpace(c) || strings.ContainsRune("{},'#", c) {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// message parses text and arguments until the end of content or the closing brace of a sub message
+func (p *parser) message(inPlural bool) error {
+	for !p.eof() {
+		switch p.peek() {
+		case '\'':
+			p.quoted(inPlural)
+		case '{':
+			err := p.argument()
+			if err != nil {
+				return err
+			}
+		case '}':
+			return nil
+		default:
+			p.pos++
+		}
+	}
+	return nil
+}
+
+// quoted skips an apostrophe: ” is a literal apostrophe and a single one quotes
+// syntax characters until the next single apostrophe
+func (p *parser) quoted(inPlural bool) {
+	p.pos++
+	if p.eof() {
+		return
+	}
+
+	c := p.peek()
+	if c == '\'' {
+		p.pos++
+		return
+	}
+	if c != '{' && c != '}' && c != '|' && !(inPlural && c == '#') {
+		return
+	}
+
+	for !p.eof() {
+		if p.peek() == '\'' {
+			if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\'' {
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) argument() error {
+	start := p.pos
+	p.pos++
+	p.skipSpaces()
+
+	name := p.token()
+	if name == "" {
+		return p.errorf("missing argument name")
+	}
+	if !validArgName(name) {
+		return SyntaxError{Offset: start + 1, Message: fmt.Sprintf("invalid argument name '%s'", name)}
+	}
+
+	p.skipSpaces()
+	if p.eof() {
+		return SyntaxError{Offset: start, Message: "unclosed argument"}
+	}
+	if p.peek() == '}' {
+		p.pos++
+		return p.addArg(start, name, NoneArgType)
+	}
+	if p.peek() != ',' {
+		return p.errorf("expected ',' or '}'")
+	}
+	p.pos++
+	p.skipSpaces()
+
+	argType := p.token()
+	p.skipSpaces()
+	switch argType {
+	case NumberArgType, DateArgType, TimeArgType, SpelloutArgType, OrdinalArgType, DurationArgType:
+		err := p.addArg(start, name, argType)
+		if err != nil {
+			return err
+		}
+		if p.eof() {
+			return SyntaxError{Offset: start, Message: "unclosed argument"}
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return nil
+		}
+		if p.peek() != ',' {
+			return p.errorf("expected ',' or '}'")
+		}
+		p.pos++
+		return p.style(start)
+	case PluralArgType, SelectOrdinalArgType, SelectArgType:
+		err := p.addArg(start, name, argType)
+		if err != nil {
+			return err
+		}
+		if p.eof() || p.peek() != ',' {
+			return p.errorf("expected ',' after %s", argType)
+		}
+		p.pos++
+		return p.options(start, argType != SelectArgType)
+	case "":
+		return p.errorf("missing argument type")
+	default:
+		return p.errorf("unknown argument type '%s'", argType)
+	}
+}
+
+func (p *parser) addArg(start int, name string, argType string) error {
+	current, ok := p.args[name]
+	if ok && current != argType && current != NoneArgType && argType != NoneArgType {
+		return SyntaxError{Offset: start, Message: fmt.Sprintf("argument '%s' used as %s and %s", name, current, argType)}
+	}
+	if !ok || current == NoneArgType {
+		p.args[name] = argType
+	}
+	return nil
+}
+
+// style skips the style of a simple argument until its closing brace
+func (p *parser) style(start int) error {
+	depth := 0
+	for !p.eof() {
+		switch p.peek() {
+		case '\'':
+			p.quoted(false)
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+			depth--
+		}
+		p.pos++
+	}
+	return SyntaxError{Offset: start, Message: "unclosed argument"}
+}
+
+// options parses the selectors with their sub messages of plural, selectordinal and select arguments
+func (p *parser) options(start int, isPlural bool) error {
+	selectors := make(map[string]bool)
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return SyntaxError{Offset: start, Message: "unclosed argument"}
+		}
+		if p.peek() == '}' {
+			p.pos++
+			break
+		}
+
+		selectorStart := p.pos
+		selector := p.token()
+		if selector == "" {
+			return p.errorf("expected selector")
+		}
+
+		if isPlural && len(selectors) == 0 && strings.HasPrefix(selector, "offset:") {
+			offset := strings.TrimPrefix(selector, "offset:")
+			if offset == "" {
+				p.skipSpaces()
+				offset = p.token()
+			}
+			if !isDigits(offset) {
+				return SyntaxError{Offset: selectorStart, Message: "invalid plural offset"}
+			}
+			continue
+		}
+
+		if isPlural && !validPluralSelector(selector) {
+			return SyntaxError{Offset: selectorStart, Message: fmt.Sprintf("invalid plural selector '%s'", selector)}
+		}
+		if selectors[selector] {
+			return SyntaxError{Offset: selectorStart, Message: fmt.Sprintf("duplicate selector '%s'", selector)}
+		}
+		selectors[selector] = true
+
+		p.skipSpaces()
+		if p.eof() || p.peek() != '{' {
+			return p.errorf("expected '{' after selector '%s'", selector)
+		}
+		p.pos++
+
+		err := p.message(isPlural)
+		if err != nil {
+			return err
+		}
+		if p.eof() {
+			return SyntaxError{Offset: selectorStart, Message: fmt.Sprintf("unclosed message of selector '%s'", selector)}
+		}
+		p.pos++
+	}
+
+	if !selectors["other"] {
+		return SyntaxError{Offset: start, Message: "missing 'other' selector"}
+	}
+	return nil
+}
+
+func validArgName(name string) bool {
+	if isDigits(name) {
+		return name == "0" || name[0] != '0'
+	}
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return !unicode.IsDigit([]rune(name)[0])
+}
+
+func validPluralSelector(selector string) bool {
+	if strings.HasPrefix(selector, "=") {
+		return isDigits(selector[1:])
+	}
+	for _, s := range pluralSelectors {
+		if s == selector {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// placeholder problems found comparing a translation with its reference
+const (
+	MissingPlaceholder      = "missing"
+	UnexpectedPlaceholder   = "unexpected"
+	TypeMismatchPlaceholder = "type-mismatch"
+)
+
+// PlaceholderError reports a placeholder of a translation inconsistent with the reference one
+type PlaceholderError struct {
+	Placeholder  string
+	Problem      string
+	ExpectedType string
+	ActualType   string
+}
+
+// CompareArguments returns the placeholders of translation not matching reference ones, sorted by name
+func CompareArguments(reference map[string]string, translation map[string]string) []PlaceholderError {
+	result := make([]PlaceholderError, 0)
+
+	for name, refType := range reference {
+		actualType, ok := translation[name]
+		if !ok {
+			result = append(result, PlaceholderError{Placeholder: name, Problem: MissingPlaceholder, ExpectedType: refType})
+			continue
+		}
+		if refType != actualType && refType != NoneArgType && actualType != NoneArgType {
+			result = append(result, PlaceholderError{Placeholder: name, Problem: TypeMismatchPlaceholder, ExpectedType: refType, ActualType: actualType})
+		}
+	}
+
+	for name, actualType := range translation {
+		if _, ok := reference[name]; !ok {
+			result = append(result, PlaceholderError{Placeholder: name, Problem: UnexpectedPlaceholder, ActualType: actualType})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Placeholder < result[j].Placeholder
+	})
+	return result
+}
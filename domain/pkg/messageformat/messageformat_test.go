@@ -0,0 +1,164 @@
+package messageformat
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+	}{
+		{"plain text", "Hello world", map[string]string{}},
+		{"empty", "", map[string]string{}},
+		{"simple argument", "Hello {name}", map[string]string{"name": NoneArgType}},
+		{"spaces in argument", "Hello { name }", map[string]string{"name": NoneArgType}},
+		{"numbered argument", "{0} of {1}", map[string]string{"0": NoneArgType, "1": NoneArgType}},
+		{"typed argument", "{count, number}", map[string]string{"count": NumberArgType}},
+		{"argument with style", "{at, date, short} {price, number, ::currency/EUR}", map[string]string{"at": DateArgType, "price": NumberArgType}},
+		{"style with braces", "{n, number, {x}}", map[string]string{"n": NumberArgType}},
+		{"untyped and typed use", "{n} {n, number}", map[string]string{"n": NumberArgType}},
+		{
+			"plural",
+			"{count, plural, one {# item} other {# items}}",
+			map[string]string{"count": PluralArgType},
+		},
+		{
+			"plural with offset and exact selectors",
+			"{guests, plural, offset:1 =0 {nobody} =1 {{host}} other {{host} and # others}}",
+			map[string]string{"guests": PluralArgType, "host": NoneArgType},
+		},
+		{
+			"plural with spaced offset",
+			"{n, plural, offset: 2 other {#}}",
+			map[string]string{"n": PluralArgType},
+		},
+		{
+			"select",
+			"{gender, select, male {He} female {She} other {They}}",
+			map[string]string{"gender": SelectArgType},
+		},
+		{
+			"selectordinal",
+			"{pos, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			map[string]string{"pos": SelectOrdinalArgType},
+		},
+		{
+			"nested plurals",
+			"{files, plural, one {{folders, plural, one {# file in # folder} other {# file in # folders}}} other {{folders, plural, one {# files in # folder} other {# files in # folders}}}}",
+			map[string]string{"files": PluralArgType, "folders": PluralArgType},
+		},
+		{
+			"plural nested in select",
+			"{gender, select, female {{n, plural, one {She has # {item}} other {She has # items}}} other {{n, plural, other {They have #}}}}",
+			map[string]string{"gender": SelectArgType, "n": PluralArgType, "item": NoneArgType},
+		},
+		{"escaped apostrophe", "It''s {name}", map[string]string{"name": NoneArgType}},
+		{"quoted braces", "'{name}' is literal", map[string]string{}},
+		{"apostrophe before text", "l'{name}", map[string]string{}},
+		{"quoted hash in plural", "{n, plural, other {'#' is #}}", map[string]string{"n": PluralArgType}},
+		{"unclosed quote", "'{name}", map[string]string{}},
+		{"trailing apostrophe", "end'", map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offset  int
+	}{
+		{"unexpected closing brace", "Hello}", 5},
+		{"missing argument name", "Hello {}", 7},
+		{"invalid argument name", "{first*name}", 1},
+		{"space in argument name", "{first name}", 7},
+		{"argument name with leading digit", "{1st}", 1},
+		{"argument name with leading zero", "{01}", 1},
+		{"unclosed argument", "Hello {name", 6},
+		{"unclosed typed argument", "{n, number", 0},
+		{"unclosed style", "{n, number, ::percent", 0},
+		{"missing argument type", "{n, }", 4},
+		{"unknown argument type", "{n, currency}", 12},
+		{"missing comma after plural", "{n, plural}", 10},
+		{"unclosed plural", "{n, plural, other {#}", 0},
+		{"missing other selector", "{n, plural, one {#}}", 0},
+		{"invalid plural selector", "{n, plural, single {#} other {#}}", 12},
+		{"invalid exact selector", "{n, plural, =x {#} other {#}}", 12},
+		{"invalid plural offset", "{n, plural, offset:x other {#}}", 12},
+		{"duplicate selector", "{g, select, male {He} male {He} other {They}}", 22},
+		{"missing sub message", "{g, select, male He other {They}}", 17},
+		{"unclosed sub message", "{g, select, other {They", 12},
+		{"argument types mismatch", "{n, number} {n, plural, other {#}}", 12},
+		{"malformed nested plural", "{files, plural, other {{folders, plural, one {#}}}}", 23},
+		{"unclosed nested plural", "{files, plural, other {{folders, plural, other {#}}", 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.content)
+			var syntaxErr SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("got %v, %v, want syntax error", got, err)
+			}
+			if syntaxErr.Offset != tt.offset {
+				t.Errorf("got offset %d (%v), want %d", syntaxErr.Offset, err, tt.offset)
+			}
+		})
+	}
+}
+
+func TestCompareArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		reference   map[string]string
+		translation map[string]string
+		want        []PlaceholderError
+	}{
+		{
+			"same arguments",
+			map[string]string{"name": NoneArgType, "n": PluralArgType},
+			map[string]string{"name": NoneArgType, "n": PluralArgType},
+			[]PlaceholderError{},
+		},
+		{
+			"untyped matches any type",
+			map[string]string{"n": NoneArgType, "m": NumberArgType},
+			map[string]string{"n": NumberArgType, "m": NoneArgType},
+			[]PlaceholderError{},
+		},
+		{
+			"missing, unexpected and mismatch sorted by name",
+			map[string]string{"c": NoneArgType, "a": NumberArgType},
+			map[string]string{"a": PluralArgType, "b": NoneArgType},
+			[]PlaceholderError{
+				{Placeholder: "a", Problem: TypeMismatchPlaceholder, ExpectedType: NumberArgType, ActualType: PluralArgType},
+				{Placeholder: "b", Problem: UnexpectedPlaceholder, ActualType: NoneArgType},
+				{Placeholder: "c", Problem: MissingPlaceholder, ExpectedType: NoneArgType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareArguments(tt.reference, tt.translation)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
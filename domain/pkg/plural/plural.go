@@ -0,0 +1,128 @@
+package plural
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CLDR plural categories
+const (
+	Zero  = "zero"
+	One   = "one"
+	Two   = "two"
+	Few   = "few"
+	Many  = "many"
+	Other = "other"
+)
+
+var Categories = []string{Zero, One, Two, Few, Many, Other}
+
+var (
+	ErrMissingCategory = errors.New("missing plural category")
+	ErrUnknownCategory = errors.New("plural category not used by lang")
+	ErrEmptyForm       = errors.New("empty plural form")
+)
+
+// cardinalCategories are the CLDR cardinal plural categories by base language;
+// languages not listed use one/other
+var cardinalCategories = map[string][]string{
+	// only other
+	"ja": {Other}, "zh": {Other}, "ko": {Other}, "vi": {Other}, "th": {Other}, "id": {Other}, "ms": {Other}, "lo": {Other}, "my": {Other},
+	// one, many, other
+	"es": {One, Many, Other}, "fr": {One, Many, Other}, "it": {One, Many, Other}, "pt": {One, Many, Other}, "ca": {One, Many, Other},
+	// one, few, other
+	"ro": {One, Few, Other}, "hr": {One, Few, Other}, "sr": {One, Few, Other}, "bs": {One, Few, Other},
+	// one, few, many, other
+	"pl": {One, Few, Many, Other}, "ru": {One, Few, Many, Other}, "uk": {One, Few, Many, Other}, "be": {One, Few, Many, Other},
+	"cs": {One, Few, Many, Other}, "sk": {One, Few, Many, Other}, "lt": {One, Few, Many, Other},
+	// zero, one, other
+	"lv": {Zero, One, Other},
+	// one, two, other
+	"he": {One, Two, Other},
+	// one, two, few, other
+	"sl": {One, Two, Few, Other},
+	// one, two, few, many, other
+	"ga": {One, Two, Few, Many, Other},
+	// all categories
+	"ar": {Zero, One, Two, Few, Many, Other}, "cy": {Zero, One, Two, Few, Many, Other},
+}
+
+// baseLang returns the language subtag of a lang code as en from en-US or en_US
+func baseLang(lang string) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	return strings.ToLower(base)
+}
+
+// RequiredCategories returns the plural categories needed by lang
+func RequiredCategories(lang string) []string {
+	categories, ok := cardinalCategories[baseLang(lang)]
+	if !ok {
+		return []string{One, Other}
+	}
+	return categories
+}
+
+// Validate checks that forms have exactly the plural categories required by lang
+func Validate(lang string, forms Forms) error {
+	return ValidateCategories(RequiredCategories(lang), forms)
+}
+
+// ValidateCategories checks that forms have exactly the required plural categories
+func ValidateCategories(required []string, forms Forms) error {
+	errs := make([]error, 0)
+
+	for _, category := range required {
+		content, ok := forms[category]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrMissingCategory, category))
+			continue
+		}
+		if content == "" {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrEmptyForm, category))
+		}
+	}
+
+	for category := range forms {
+		if !slices.Contains(required, category) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownCategory, category))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Forms are the contents of a translation by plural category
+type Forms map[string]string
+
+// Value implements driver.Valuer to persist forms as json
+func (forms Forms) Value() (driver.Value, error) {
+	if forms == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(forms)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner to read forms from json
+func (forms *Forms) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*forms = Forms{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type %T for plural forms", src)
+	}
+	return json.Unmarshal(data, forms)
+}
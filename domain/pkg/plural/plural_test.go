@@ -0,0 +1,107 @@
+package plural
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRequiredCategories(t *testing.T) {
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{"en", []string{One, Other}},
+		{"en-US", []string{One, Other}},
+		{"de", []string{One, Other}},
+		{"ja", []string{Other}},
+		{"zh_Hant", []string{Other}},
+		{"IT", []string{One, Many, Other}},
+		{"pt-BR", []string{One, Many, Other}},
+		{"ro", []string{One, Few, Other}},
+		{"ru", []string{One, Few, Many, Other}},
+		{"lv", []string{Zero, One, Other}},
+		{"he", []string{One, Two, Other}},
+		{"sl", []string{One, Two, Few, Other}},
+		{"ga", []string{One, Two, Few, Many, Other}},
+		{"ar-EG", []string{Zero, One, Two, Few, Many, Other}},
+		{"", []string{One, Other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := RequiredCategories(tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		lang  string
+		forms Forms
+		errs  []error
+	}{
+		{"complete", "en", Forms{One: "# item", Other: "# items"}, nil},
+		{"other only", "ja", Forms{Other: "# 件"}, nil},
+		{"all categories", "ar", Forms{Zero: "0", One: "1", Two: "2", Few: "3", Many: "11", Other: "100"}, nil},
+		{"missing category", "ru", Forms{One: "1", Few: "2", Other: "5"}, []error{ErrMissingCategory}},
+		{"empty form", "en", Forms{One: "", Other: "# items"}, []error{ErrEmptyForm}},
+		{"category not used by lang", "en", Forms{One: "1", Two: "2", Other: "#"}, []error{ErrUnknownCategory}},
+		{"unknown category", "en", Forms{One: "1", "several": "#", Other: "#"}, []error{ErrUnknownCategory}},
+		{"no forms", "en", Forms{}, []error{ErrMissingCategory}},
+		{"all problems", "pl", Forms{One: "", Few: "#", Zero: "0"}, []error{ErrMissingCategory, ErrEmptyForm, ErrUnknownCategory}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.lang, tt.forms)
+			if tt.errs == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.errs {
+				if !errors.Is(err, want) {
+					t.Errorf("got %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormsValueScan(t *testing.T) {
+	tests := []struct {
+		name  string
+		forms Forms
+		want  Forms
+	}{
+		{"forms", Forms{One: "# item", Other: "# items"}, Forms{One: "# item", Other: "# items"}},
+		{"nil", nil, Forms{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.forms.Value()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := Forms{}
+			err = got.Scan(value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var forms Forms
+	if err := forms.Scan(nil); err != nil || forms == nil {
+		t.Errorf("got %v, %v, want empty forms", forms, err)
+	}
+	if err := forms.Scan(42); err == nil {
+		t.Errorf("got nil error scanning an int")
+	}
+}
@@ -0,0 +1,80 @@
+// Package textlen measures translation content as characters (code points) and as graphemes,
+// the user perceived characters
+package textlen
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const zeroWidthJoiner = '\u200d'
+
+// Chars returns the number of unicode code points of s
+func Chars(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// Graphemes returns the number of grapheme clusters of s: combining marks, variation selectors,
+// emoji modifiers and tags are joined to the previous rune, as the runes around a zero width joiner
+// and the pairs of regional indicators (flags); CR LF counts as one
+func Graphemes(s string) int {
+	count := 0
+	var prev rune
+	regionalIndicators := 0
+	for i, r := range s {
+		if i > 0 && extends(prev, r, regionalIndicators) {
+			if isRegionalIndicator(r) {
+				regionalIndicators++
+			}
+			prev = r
+			continue
+		}
+
+		count++
+		regionalIndicators = 0
+		if isRegionalIndicator(r) {
+			regionalIndicators = 1
+		}
+		prev = r
+	}
+	return count
+}
+
+// extends reports if r belongs to the grapheme cluster ending with prev
+func extends(prev rune, r rune, regionalIndicators int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == zeroWidthJoiner, r == zeroWidthJoiner:
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef:
+		// variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// emoji skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		// emoji tag sequences
+		return true
+	case isRegionalIndicator(r):
+		return isRegionalIndicator(prev) && regionalIndicators%2 == 1
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// Exceeds reports if s is longer than maxChars or maxGraphemes; a zero max is no limit
+func Exceeds(s string, maxChars int, maxGraphemes int) bool {
+	if maxChars > 0 && Chars(s) > maxChars {
+		return true
+	}
+	if maxGraphemes > 0 && Graphemes(s) > maxGraphemes {
+		return true
+	}
+	return false
+}
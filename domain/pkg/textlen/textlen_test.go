@@ -0,0 +1,68 @@
+package textlen
+
+import "testing"
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		chars     int
+		graphemes int
+	}{
+		{"empty", "", 0, 0},
+		{"ascii", "hello", 5, 5},
+		{"accented precomposed", "café", 4, 4},
+		{"combining mark", "cafe\u0301", 5, 4},
+		{"multiple combining marks", "a\u0323\u0301b", 4, 2},
+		{"devanagari spacing mark", "\u0915\u093f", 2, 1},
+		{"cjk", "日本語", 3, 3},
+		{"crlf", "a\r\nb", 4, 3},
+		{"lf cr", "a\n\rb", 4, 4},
+		{"emoji", "😀", 1, 1},
+		{"skin tone modifier", "\U0001F44D\U0001F3FD", 2, 1},
+		{"variation selector", "\u2764\ufe0f", 2, 1},
+		{"zwj family", "\U0001F469\u200d\U0001F469\u200d\U0001F467", 5, 1},
+		{"flag", "🇮🇹", 2, 1},
+		{"two flags", "🇮🇹🇫🇷", 4, 2},
+		{"odd regional indicators", "🇮🇹🇫", 3, 2},
+		{"tag sequence", "🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F", 7, 1},
+		{"mixed", "Hi \U0001F44B\U0001F3FB!", 6, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chars(tt.s); got != tt.chars {
+				t.Errorf("got %d chars, want %d", got, tt.chars)
+			}
+			if got := Graphemes(tt.s); got != tt.graphemes {
+				t.Errorf("got %d graphemes, want %d", got, tt.graphemes)
+			}
+		})
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	tests := []struct {
+		name         string
+		s            string
+		maxChars     int
+		maxGraphemes int
+		want         bool
+	}{
+		{"no limits", "hello", 0, 0, false},
+		{"within chars", "hello", 5, 0, false},
+		{"over chars", "hello", 4, 0, true},
+		{"within graphemes", "🇮🇹🇫🇷", 0, 2, false},
+		{"over graphemes", "🇮🇹🇫🇷", 0, 1, true},
+		{"chars over graphemes within", "🇮🇹🇫🇷", 3, 2, true},
+		{"both within", "cafe\u0301", 5, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Exceeds(tt.s, tt.maxChars, tt.maxGraphemes); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+-- +goose up
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS localeitems_list_content_trgm_index ON locale.localeitems_list USING gin (content gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS localeitems_list_content_fts_index ON locale.localeitems_list USING gin (to_tsvector('simple', content));
+
+CREATE INDEX IF NOT EXISTS localeitems_list_updated_at_index ON locale.localeitems_list (updated_at);
+
+-- +goose down
+DROP INDEX IF EXISTS locale.localeitems_list_updated_at_index;
+DROP INDEX IF EXISTS locale.localeitems_list_content_fts_index;
+DROP INDEX IF EXISTS locale.localeitems_list_content_trgm_index;
@@ -0,0 +1,9 @@
+-- +goose up
+
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'draft';
+
+CREATE INDEX IF NOT EXISTS localeitems_list_context_status_index ON locale.localeitems_list (context, status);
+
+-- +goose down
+DROP INDEX IF EXISTS locale.localeitems_list_context_status_index;
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS status;
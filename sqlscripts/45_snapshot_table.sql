@@ -0,0 +1,13 @@
+-- +goose up
+
+CREATE TABLE IF NOT EXISTS locale.localeitem_snapshot (
+  aggregate_id varchar(64) NOT NULL,
+  version int NOT NULL,
+  schema_version int NOT NULL,
+  created_at timestamptz NOT NULL,
+  data text NOT NULL,
+  CONSTRAINT localeitem_snapshot_pkey PRIMARY KEY (aggregate_id)
+);
+
+-- +goose down
+DROP TABLE locale.localeitem_snapshot;
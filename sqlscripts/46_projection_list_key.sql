@@ -0,0 +1,9 @@
+-- +goose up
+
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS key varchar(128) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS localeitems_list_context_key_index ON locale.localeitems_list (context, key);
+
+-- +goose down
+DROP INDEX IF EXISTS locale.localeitems_list_context_key_index;
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS key;
@@ -0,0 +1,6 @@
+-- +goose up
+
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS plurals jsonb NOT NULL DEFAULT '{}';
+
+-- +goose down
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS plurals;
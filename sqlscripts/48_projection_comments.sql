@@ -0,0 +1,25 @@
+-- +goose up
+
+CREATE TABLE IF NOT EXISTS locale.localeitem_comments (
+  comment_id varchar(64) NOT NULL,
+  aggregate_id varchar(64) NOT NULL,
+  parent_id varchar(64) NOT NULL DEFAULT '',
+  lang varchar(12) NOT NULL DEFAULT '',
+  content text NOT NULL,
+  created_by varchar(64) NOT NULL,
+  created_at timestamptz NOT NULL,
+  updated_at timestamptz NOT NULL,
+  is_resolved boolean NOT NULL DEFAULT false,
+  resolved_by varchar(64) NOT NULL DEFAULT '',
+  resolved_at timestamptz NOT NULL,
+  CONSTRAINT localeitem_comments_pkey PRIMARY KEY (comment_id)
+);
+
+CREATE INDEX IF NOT EXISTS localeitem_comments_aggregate_index ON locale.localeitem_comments (aggregate_id);
+
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS open_comments int NOT NULL DEFAULT 0;
+
+-- +goose down
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS open_comments;
+DROP INDEX IF EXISTS locale.localeitem_comments_aggregate_index;
+DROP TABLE locale.localeitem_comments;
@@ -0,0 +1,6 @@
+-- +goose up
+
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS is_stale boolean NOT NULL DEFAULT false;
+
+-- +goose down
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS is_stale;
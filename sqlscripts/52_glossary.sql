@@ -0,0 +1,20 @@
+-- +goose up
+
+CREATE TABLE IF NOT EXISTS locale.glossary_term (
+  term_id varchar(64) NOT NULL,
+  term varchar(256) NOT NULL,
+  lang varchar(12) NOT NULL,
+  case_sensitive boolean NOT NULL DEFAULT false,
+  forbidden boolean NOT NULL DEFAULT false,
+  translations jsonb NOT NULL DEFAULT '{}',
+  notes text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL,
+  updated_by varchar(64) NOT NULL,
+  CONSTRAINT glossary_term_pkey PRIMARY KEY (term_id)
+);
+
+CREATE INDEX IF NOT EXISTS glossary_term_lang_index ON locale.glossary_term (lang);
+
+-- +goose down
+DROP INDEX IF EXISTS locale.glossary_term_lang_index;
+DROP TABLE locale.glossary_term;
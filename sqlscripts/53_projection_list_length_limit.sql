@@ -0,0 +1,8 @@
+-- +goose up
+
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS max_chars int NOT NULL DEFAULT 0;
+ALTER TABLE locale.localeitems_list ADD COLUMN IF NOT EXISTS max_graphemes int NOT NULL DEFAULT 0;
+
+-- +goose down
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS max_graphemes;
+ALTER TABLE locale.localeitems_list DROP COLUMN IF EXISTS max_chars;
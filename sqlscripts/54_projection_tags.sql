@@ -0,0 +1,13 @@
+-- +goose up
+
+CREATE TABLE IF NOT EXISTS locale.localeitem_tags (
+  aggregate_id varchar(64) NOT NULL,
+  tag varchar(64) NOT NULL,
+  CONSTRAINT localeitem_tags_pkey PRIMARY KEY (aggregate_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS localeitem_tags_tag_index ON locale.localeitem_tags (tag);
+
+-- +goose down
+DROP INDEX IF EXISTS locale.localeitem_tags_tag_index;
+DROP TABLE locale.localeitem_tags;
@@ -0,0 +1,24 @@
+-- +goose up
+
+CREATE TABLE IF NOT EXISTS locale.lang (
+  code varchar(35) NOT NULL,
+  display_name varchar(128) NOT NULL,
+  direction varchar(3) NOT NULL DEFAULT 'ltr',
+  plural_categories jsonb NOT NULL DEFAULT '[]',
+  enabled boolean NOT NULL DEFAULT true,
+  version int NOT NULL DEFAULT 0,
+  updated_at timestamptz NOT NULL,
+  updated_by varchar(64) NOT NULL,
+  CONSTRAINT lang_pkey PRIMARY KEY (code)
+);
+
+-- BCP 47 codes with script, region and variants are longer than 12
+ALTER TABLE locale.localeitems_list ALTER COLUMN lang TYPE varchar(35);
+ALTER TABLE locale.localeitem_comments ALTER COLUMN lang TYPE varchar(35);
+ALTER TABLE locale.glossary_term ALTER COLUMN lang TYPE varchar(35);
+
+-- +goose down
+ALTER TABLE locale.glossary_term ALTER COLUMN lang TYPE varchar(12);
+ALTER TABLE locale.localeitem_comments ALTER COLUMN lang TYPE varchar(12);
+ALTER TABLE locale.localeitems_list ALTER COLUMN lang TYPE varchar(12);
+DROP TABLE locale.lang;
@@ -0,0 +1,18 @@
+-- +goose up
+
+CREATE TABLE IF NOT EXISTS locale.contexts (
+  context_id varchar(64) NOT NULL,
+  name varchar(64) NOT NULL,
+  description text NOT NULL DEFAULT '',
+  required_langs jsonb NOT NULL DEFAULT '[]',
+  default_reference_lang varchar(35) NOT NULL DEFAULT '',
+  is_archived boolean NOT NULL DEFAULT false,
+  version int NOT NULL DEFAULT 0,
+  updated_at timestamptz NOT NULL,
+  updated_by varchar(64) NOT NULL,
+  CONSTRAINT contexts_pkey PRIMARY KEY (context_id),
+  CONSTRAINT contexts_name_ukey UNIQUE (name)
+);
+
+-- +goose down
+DROP TABLE locale.contexts;
@@ -0,0 +1,13 @@
+-- +goose up
+
+ALTER TABLE locale.contexts ADD COLUMN IF NOT EXISTS parent varchar(64) NOT NULL DEFAULT '';
+UPDATE locale.contexts SET parent = regexp_replace(name, '/?[^/]*$', '');
+
+CREATE INDEX IF NOT EXISTS contexts_parent_index ON locale.contexts (parent);
+
+CREATE INDEX IF NOT EXISTS localeitems_list_context_pattern_index ON locale.localeitems_list (context varchar_pattern_ops);
+
+-- +goose down
+DROP INDEX IF EXISTS locale.localeitems_list_context_pattern_index;
+DROP INDEX IF EXISTS locale.contexts_parent_index;
+ALTER TABLE locale.contexts DROP COLUMN IF EXISTS parent;
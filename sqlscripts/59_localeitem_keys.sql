@@ -0,0 +1,26 @@
+-- +goose up
+
+-- context and key of each locale item, written with its events by the locale item writer: keys are
+-- unique in context against all stored items, also the ones not yet in projections
+CREATE TABLE IF NOT EXISTS locale.localeitem_keys (
+  aggregate_id varchar(64) NOT NULL,
+  context varchar(64) NOT NULL,
+  key varchar(128) NOT NULL DEFAULT '',
+  CONSTRAINT localeitem_keys_pkey PRIMARY KEY (aggregate_id)
+);
+
+-- items created before keep their key, only the first updated one if used by more items in context
+INSERT INTO locale.localeitem_keys (aggregate_id, context, key)
+SELECT
+  aggregate_id,
+  context,
+  CASE WHEN key <> '' AND row_number() OVER (PARTITION BY context, key ORDER BY updated_at, aggregate_id) > 1 THEN '' ELSE key END
+FROM locale.localeitems_list
+WHERE is_lang_reference
+ON CONFLICT (aggregate_id) DO NOTHING;
+
+CREATE UNIQUE INDEX IF NOT EXISTS localeitem_keys_context_key_index ON locale.localeitem_keys (context, key) WHERE key <> '';
+
+-- +goose down
+DROP INDEX IF EXISTS locale.localeitem_keys_context_key_index;
+DROP TABLE IF EXISTS locale.localeitem_keys;
@@ -0,0 +1,16 @@
+-- +goose up
+
+-- names reserved by contexts before their created or renamed event is stored: a name is used by one
+-- context also while the contexts projection is not updated yet
+CREATE TABLE IF NOT EXISTS locale.context_names (
+  name varchar(64) NOT NULL,
+  context_id varchar(64) NOT NULL,
+  CONSTRAINT context_names_pkey PRIMARY KEY (name)
+);
+
+INSERT INTO locale.context_names (name, context_id)
+SELECT name, context_id FROM locale.contexts
+ON CONFLICT (name) DO NOTHING;
+
+-- +goose down
+DROP TABLE IF EXISTS locale.context_names;
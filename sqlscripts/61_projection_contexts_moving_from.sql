@@ -0,0 +1,6 @@
+-- +goose up
+
+ALTER TABLE locale.contexts ADD COLUMN IF NOT EXISTS moving_from varchar(64) NOT NULL DEFAULT '';
+
+-- +goose down
+ALTER TABLE locale.contexts DROP COLUMN IF EXISTS moving_from;